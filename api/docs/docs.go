@@ -31,6 +31,12 @@ const docTemplate = `{
                 "summary": "get all authors",
                 "operationId": "get_all_authors_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated ids to fetch, in order; other filters are ignored",
+                        "name": "ids",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Search Query",
@@ -48,6 +54,18 @@ const docTemplate = `{
                         "description": "offset",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,firstname",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "read the state at this time, RFC 3339 or YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -79,6 +97,12 @@ const docTemplate = `{
                 "summary": "Create an author",
                 "operationId": "create_author",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "description": "Author Body",
                         "name": "author",
@@ -87,10 +111,57 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.CreateAuthor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to get only the new id",
+                        "name": "Prefer",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
+                        "description": "Created author, its URL in Location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/duplicates": {
+            "get": {
+                "description": "Pairs of authors whose normalized names are similar, best matches first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Author"
+                ],
+                "summary": "Find likely duplicate authors",
+                "operationId": "get_author_duplicates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Minimum similarity between 0 and 1, default 0.9",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
                         "description": "Success Response",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
@@ -118,10 +189,22 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Author ID",
+                        "description": "Author ID or slug",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,firstname",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "read the state at this time, RFC 3339 or YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -131,6 +214,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "301": {
+                        "description": "Author was merged or renamed, see Location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request Error",
                         "schema": {
@@ -158,6 +247,12 @@ const docTemplate = `{
                 "summary": "Update Author",
                 "operationId": "update_author_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Author ID",
@@ -173,11 +268,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.UpdateAuthor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to get only the affected row count",
+                        "name": "Prefer",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success Response",
+                        "description": "Updated author",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -203,6 +304,12 @@ const docTemplate = `{
                 "summary": "delete an author by id",
                 "operationId": "delete_author_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Author ID",
@@ -233,6 +340,107 @@ const docTemplate = `{
                 }
             }
         },
+        "/authors/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Author"
+                ],
+                "summary": "Get the change history of an author",
+                "operationId": "get_author_history_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Author ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes, newest first",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{id}/merge": {
+            "post": {
+                "description": "Move every book of the source authors to this author, delete the sources and redirect their ids here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Author"
+                ],
+                "summary": "Merge authors",
+                "operationId": "merge_authors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target Author ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Source authors",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeAuthors"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/book_category": {
             "get": {
                 "produces": [
@@ -244,6 +452,12 @@ const docTemplate = `{
                 "summary": "Get all book categories",
                 "operationId": "get_all_book_categories",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated ids to fetch, in order; other filters are ignored",
+                        "name": "ids",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Search Query",
@@ -261,6 +475,18 @@ const docTemplate = `{
                         "description": "offset",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,category_name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "read the state at this time, RFC 3339 or YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -292,6 +518,12 @@ const docTemplate = `{
                 "summary": "Create a book category",
                 "operationId": "create_book_category_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "description": "Author Body",
                         "name": "author",
@@ -300,11 +532,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.CreateBookCategory"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to get only the new id",
+                        "name": "Prefer",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Success Response",
+                        "description": "Created category, its URL in Location",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -331,10 +569,22 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Book Category ID",
+                        "description": "Book Category ID or slug",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,category_name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "read the state at this time, RFC 3339 or YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -344,6 +594,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "301": {
+                        "description": "Category was renamed, see Location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request Error",
                         "schema": {
@@ -371,6 +627,12 @@ const docTemplate = `{
                 "summary": "Update book category",
                 "operationId": "update_author_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Book Category ID",
@@ -386,11 +648,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.UpdateBookCategory"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to get only the affected row count",
+                        "name": "Prefer",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success Response",
+                        "description": "Updated category",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -416,6 +684,12 @@ const docTemplate = `{
                 "summary": "delete an book category by id",
                 "operationId": "delete_book_category_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Book Category ID",
@@ -446,22 +720,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/books": {
+        "/book_category/{id}/history": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Book"
+                    "BookCategory"
                 ],
-                "summary": "Get all books",
-                "operationId": "get_all_books_id",
+                "summary": "Get the change history of a book category",
+                "operationId": "get_book_category_history_id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Book Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -478,46 +753,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success Response",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Some bad request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "has no relation with others",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Book"
-                ],
-                "summary": "Create a book",
-                "operationId": "create_book_id",
-                "parameters": [
-                    {
-                        "description": "Book Body",
-                        "name": "author",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateBook"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Success Response",
+                        "description": "Changes, newest first",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -531,7 +767,158 @@ const docTemplate = `{
                 }
             }
         },
-        "/books/{id}": {
+        "/books": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Book"
+                ],
+                "summary": "Get all books",
+                "operationId": "get_all_books_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated ids to fetch, in order; other filters are ignored",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 639-1 language code",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hardcover",
+                            "paperback",
+                            "ebook",
+                            "audiobook"
+                        ],
+                        "type": "string",
+                        "description": "format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "published in or after this year",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "published in or before this year",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort field, prefix with - for descending, e.g. -average_rating",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,book_name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "read the state at this time, RFC 3339 or YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Some bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "has no relation with others",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Book"
+                ],
+                "summary": "Create a book",
+                "operationId": "create_book_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Book Body",
+                        "name": "author",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBook"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to get only the new id",
+                        "name": "Prefer",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created book, its URL in Location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -544,10 +931,22 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Book Category ID",
+                        "description": "Book ID or slug",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,book_name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "read the state at this time, RFC 3339 or YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -557,6 +956,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "301": {
+                        "description": "Book was renamed, see Location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request Error",
                         "schema": {
@@ -584,6 +989,12 @@ const docTemplate = `{
                 "summary": "Update book",
                 "operationId": "update_book_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Book ID",
@@ -599,11 +1010,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.UpdateBook"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to get only the affected row count",
+                        "name": "Prefer",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "SUccess REsponse",
+                        "description": "Updated book",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -629,6 +1046,12 @@ const docTemplate = `{
                 "summary": "delete an book by id",
                 "operationId": "delete_book_id",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Book ID",
@@ -658,38 +1081,2333 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CreateAuthor": {
-            "type": "object",
-            "required": [
-                "firstname",
-                "lastname"
-            ],
-            "properties": {
-                "firstname": {
-                    "type": "string",
-                    "example": "John"
-                },
-                "lastname": {
-                    "type": "string",
-                    "example": "Doe"
+        },
+        "/books/{id}/copies": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy"
+                ],
+                "summary": "Get all copies of a book",
+                "operationId": "get_all_copies_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "barcode or shelf location",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "barcode must be unique across the library",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy"
+                ],
+                "summary": "Add a physical copy of a book",
+                "operationId": "create_copy_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Copy Body",
+                        "name": "copy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCopy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
                 }
             }
         },
-        "models.CreateBook": {
-            "type": "object",
-            "required": [
-                "author_id",
-                "book_name",
-                "category_id"
-            ],
-            "properties": {
-                "author_id": {
-                    "type": "string",
-                    "example": "author_id"
-                },
+        "/books/{id}/copies/{copy_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy"
+                ],
+                "summary": "Get a copy of a book by ID",
+                "operationId": "get_copy_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Copy ID",
+                        "name": "copy_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy"
+                ],
+                "summary": "Update a copy of a book",
+                "operationId": "update_copy_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Copy ID",
+                        "name": "copy_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Model",
+                        "name": "copy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCopy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Copy"
+                ],
+                "summary": "delete a copy of a book",
+                "operationId": "delete_copy_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Copy ID",
+                        "name": "copy_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/cover": {
+            "get": {
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "Book"
+                ],
+                "summary": "Get a book cover",
+                "operationId": "get_book_cover_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "original",
+                            "medium",
+                            "small"
+                        ],
+                        "type": "string",
+                        "description": "size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cover image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "accepts a JPEG or PNG image and generates medium and small thumbnails",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Book"
+                ],
+                "summary": "Upload a book cover",
+                "operationId": "upload_book_cover_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Cover image",
+                        "name": "cover",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Image too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported image type",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Book"
+                ],
+                "summary": "Get the change history of a book",
+                "operationId": "get_book_history_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes, newest first",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/holds": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hold"
+                ],
+                "summary": "Get the hold queue of a book",
+                "operationId": "get_book_holds_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "joins the FIFO queue for the next returned copy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hold"
+                ],
+                "summary": "Place a hold on a book",
+                "operationId": "create_hold_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hold Body",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateHold"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/revert": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Book"
+                ],
+                "summary": "Revert a book to a previous version",
+                "operationId": "revert_book_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "who makes the change, kept in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "version from the book's history",
+                        "name": "version",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reverted book",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/reviews": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get all reviews of a book",
+                "operationId": "get_all_reviews_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search Query",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "one review per reviewer per book",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Review a book",
+                "operationId": "create_review_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review Body",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateReview"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/reviews/{review_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get a review of a book by ID",
+                "operationId": "get_review_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Update a review of a book",
+                "operationId": "update_review_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Model",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateReview"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Review"
+                ],
+                "summary": "delete a review of a book",
+                "operationId": "delete_review_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/copies/barcode/{barcode}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy"
+                ],
+                "summary": "Look up a copy by its barcode",
+                "operationId": "get_copy_by_barcode_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "barcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Server-Sent Events of created, updated and deleted books, authors and categories. Every event carries its sequence number as id, and the data is the same JSON webhooks receive. A client reconnecting with Last-Event-ID gets the events it missed; without it the stream starts at new events.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Stream catalog changes",
+                "operationId": "stream_events_id",
+                "parameters": [
+                    {
+                        "enum": [
+                            "book",
+                            "author",
+                            "book_category"
+                        ],
+                        "type": "string",
+                        "description": "comma separated entities to stream, e.g. book,author",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Queries and mutations of books, authors and categories, which can select their author, category, books and the sibling books of their series. Operations deeper or more complex than configured are rejected before they run. The response is a standard GraphQL response rather than the usual envelope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "Run a GraphQL query or mutation",
+                "operationId": "graphql_id",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graph.Params"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the history",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Executed operation, with errors if it failed",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed or invalid operation",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hold"
+                ],
+                "summary": "Get hold by ID",
+                "operationId": "get_hold_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}/cancel": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hold"
+                ],
+                "summary": "Cancel a hold",
+                "operationId": "cancel_hold_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loan"
+                ],
+                "summary": "Get all loans",
+                "operationId": "get_all_loans_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "member_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only loans that are still out",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "due date depends on the format of the borrowed book",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loan"
+                ],
+                "summary": "Check a copy out to a member",
+                "operationId": "create_loan_id",
+                "parameters": [
+                    {
+                        "description": "Loan Body",
+                        "name": "loan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateLoan"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loan"
+                ],
+                "summary": "Get loan by ID",
+                "operationId": "get_loan_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Loan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans/{id}/fine": {
+            "get": {
+                "description": "for open loans the amount grows until the copy is returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loan"
+                ],
+                "summary": "Get the fine accrued on a loan",
+                "operationId": "get_loan_fine_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Loan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans/{id}/return": {
+            "post": {
+                "description": "charges the overdue fine to the member's ledger",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loan"
+                ],
+                "summary": "Return a borrowed copy",
+                "operationId": "return_loan_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Loan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/members": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Member"
+                ],
+                "summary": "Get all members",
+                "operationId": "get_all_members_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name or email",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "max_loans defaults to the configured borrowing limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Member"
+                ],
+                "summary": "Register a library member",
+                "operationId": "create_member_id",
+                "parameters": [
+                    {
+                        "description": "Member Body",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/members/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Member"
+                ],
+                "summary": "Get member by ID",
+                "operationId": "get_member_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Member"
+                ],
+                "summary": "Update member",
+                "operationId": "update_member_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Model",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Member"
+                ],
+                "summary": "delete a member by id",
+                "operationId": "delete_member_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/members/{id}/fines": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fine"
+                ],
+                "summary": "Get the fine ledger and balance of a member",
+                "operationId": "get_member_fines_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "the amount may not exceed the outstanding balance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fine"
+                ],
+                "summary": "Record a fine payment or waiver",
+                "operationId": "create_member_fine_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fine Body",
+                        "name": "fine",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateFine"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/members/{id}/holds": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hold"
+                ],
+                "summary": "Get the holds of a member",
+                "operationId": "get_member_holds_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get all series",
+                "operationId": "get_all_series_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search Query",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "groups books in reading order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Create a series",
+                "operationId": "create_series_id",
+                "parameters": [
+                    {
+                        "description": "Series Body",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSeries"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get series by ID",
+                "operationId": "get_series_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Update series",
+                "operationId": "update_series_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Model",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSeries"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Series"
+                ],
+                "summary": "delete a series by id",
+                "operationId": "delete_series_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/books": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get the books of a series in reading order",
+                "operationId": "get_series_books_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/books/{book_id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Put a book into a series at the given position",
+                "operationId": "add_book_to_series_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reading order position",
+                        "name": "membership",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeriesMembership"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Remove a book from a series",
+                "operationId": "remove_book_from_series_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync": {
+            "get": {
+                "description": "Books, authors and categories created, updated or deleted since a sync token, oldest change first. Every changed entity appears once with its current state, or as a tombstone if it was deleted. Without a token the whole catalog is returned. Pass next_token to the next sync; has_more tells that another page is ready right away.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Sync catalog changes",
+                "operationId": "sync_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sync token from a previous sync",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "entities per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of changes",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get all webhooks",
+                "operationId": "get_all_webhooks_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search in the URL",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribes a URL to catalog events. Deliveries are POSTed as JSON and signed in X-Catalog-Signature with HMAC-SHA256 over \"\u003cX-Catalog-Timestamp\u003e.\u003cbody\u003e\". The secret is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create a webhook",
+                "operationId": "create_webhook_id",
+                "parameters": [
+                    {
+                        "description": "Webhook Body",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created webhook with its secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get webhook by ID",
+                "operationId": "get_webhook_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update webhook",
+                "operationId": "update_webhook_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Model",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated webhook",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete webhook by ID",
+                "operationId": "delete_webhook_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get the delivery log of a webhook",
+                "operationId": "get_webhook_deliveries_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries, newest first",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get a delivery of a webhook with its attempts",
+                "operationId": "get_webhook_delivery_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/retry": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Retry a dead-lettered delivery",
+                "operationId": "retry_webhook_delivery_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery queued again",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No dead-lettered delivery with the given id",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "errors.Location": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "errors.QueryError": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.Location"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "graph.Params": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.QueryError"
+                    }
+                },
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.CreateAuthor": {
+            "type": "object",
+            "required": [
+                "aliases",
+                "firstname",
+                "lastname"
+            ],
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "J. R. R. Tolkien"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "English writer and philologist."
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "1892-01-03"
+                },
+                "death_date": {
+                    "type": "string",
+                    "example": "1973-09-02"
+                },
+                "firstname": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "lastname": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "GB"
+                }
+            }
+        },
+        "models.CreateBook": {
+            "type": "object",
+            "required": [
+                "author_id",
+                "book_name",
+                "category_id"
+            ],
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "example": "author_id"
+                },
                 "book_name": {
                     "type": "string",
                     "example": "bookname"
@@ -697,6 +3415,36 @@ const docTemplate = `{
                 "category_id": {
                     "type": "string",
                     "example": "uuid1234"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A hobbit goes on an unexpected journey."
+                },
+                "edition": {
+                    "type": "string",
+                    "example": "1st"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "hardcover",
+                        "paperback",
+                        "ebook",
+                        "audiobook"
+                    ],
+                    "example": "hardcover"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 310
+                },
+                "publication_year": {
+                    "type": "integer",
+                    "example": 1937
                 }
             }
         },
@@ -712,6 +3460,254 @@ const docTemplate = `{
                 }
             }
         },
+        "models.CreateCopy": {
+            "type": "object",
+            "required": [
+                "barcode"
+            ],
+            "properties": {
+                "acquisition_date": {
+                    "type": "string",
+                    "example": "2022-08-01"
+                },
+                "barcode": {
+                    "type": "string",
+                    "example": "31234000123456"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "good",
+                        "fair",
+                        "poor",
+                        "damaged"
+                    ],
+                    "example": "good"
+                },
+                "shelf_location": {
+                    "type": "string",
+                    "example": "B2-14"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "available",
+                        "lost",
+                        "withdrawn"
+                    ],
+                    "example": "available"
+                }
+            }
+        },
+        "models.CreateFine": {
+            "type": "object",
+            "required": [
+                "amount",
+                "kind"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "payment",
+                        "waiver"
+                    ],
+                    "example": "payment"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "paid at the front desk"
+                }
+            }
+        },
+        "models.CreateHold": {
+            "type": "object",
+            "required": [
+                "member_id"
+            ],
+            "properties": {
+                "member_id": {
+                    "type": "string",
+                    "example": "uuid1234"
+                }
+            }
+        },
+        "models.CreateLoan": {
+            "type": "object",
+            "required": [
+                "barcode",
+                "member_id"
+            ],
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "31234000123456"
+                },
+                "member_id": {
+                    "type": "string",
+                    "example": "uuid1234"
+                }
+            }
+        },
+        "models.CreateMember": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstname",
+                "lastname"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jane@example.com"
+                },
+                "firstname": {
+                    "type": "string",
+                    "example": "Jane"
+                },
+                "lastname": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "max_loans": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+998901234567"
+                }
+            }
+        },
+        "models.CreateReview": {
+            "type": "object",
+            "required": [
+                "rating",
+                "reviewer_id"
+            ],
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "reviewer_id": {
+                    "type": "string",
+                    "example": "uuid1234"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Could not put it down."
+                }
+            }
+        },
+        "models.CreateSeries": {
+            "type": "object",
+            "required": [
+                "series_name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Epic high fantasy trilogy"
+                },
+                "series_name": {
+                    "type": "string",
+                    "example": "The Lord of the Rings"
+                }
+            }
+        },
+        "models.CreateWebhook": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "book.created"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 16,
+                    "example": "a-long-shared-secret"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://search.example.com/hooks/catalog"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "librarian@example.com"
+                },
+                "after": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "before": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "entity": {
+                    "type": "string",
+                    "example": "book"
+                },
+                "entity_id": {
+                    "type": "string",
+                    "example": "uuid1234"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "book.updated"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.MergeAuthors": {
+            "type": "object",
+            "required": [
+                "source_ids"
+            ],
+            "properties": {
+                "source_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "uuid5678"
+                    ]
+                }
+            }
+        },
         "models.Response": {
             "type": "object",
             "properties": {
@@ -724,9 +3720,45 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SeriesMembership": {
+            "type": "object",
+            "required": [
+                "position"
+            ],
+            "properties": {
+                "position": {
+                    "type": "number",
+                    "example": 2.5
+                }
+            }
+        },
         "models.UpdateAuthor": {
             "type": "object",
+            "required": [
+                "aliases"
+            ],
             "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "J. R. R. Tolkien"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "Updated biography"
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "1892-01-03"
+                },
+                "death_date": {
+                    "type": "string",
+                    "example": "1973-09-02"
+                },
                 "firstname": {
                     "type": "string",
                     "example": "John Updated"
@@ -734,6 +3766,10 @@ const docTemplate = `{
                 "lastname": {
                     "type": "string",
                     "example": "Doe Updated"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "GB"
                 }
             }
         },
@@ -751,6 +3787,36 @@ const docTemplate = `{
                 "category_id": {
                     "type": "string",
                     "example": "uuid1234"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Updated description"
+                },
+                "edition": {
+                    "type": "string",
+                    "example": "2nd"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "hardcover",
+                        "paperback",
+                        "ebook",
+                        "audiobook"
+                    ],
+                    "example": "paperback"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 310
+                },
+                "publication_year": {
+                    "type": "integer",
+                    "example": 1937
                 }
             }
         },
@@ -765,6 +3831,126 @@ const docTemplate = `{
                     "example": "psychology"
                 }
             }
+        },
+        "models.UpdateCopy": {
+            "type": "object",
+            "properties": {
+                "acquisition_date": {
+                    "type": "string",
+                    "example": "2022-08-01"
+                },
+                "barcode": {
+                    "type": "string",
+                    "example": "31234000123456"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "good",
+                        "fair",
+                        "poor",
+                        "damaged"
+                    ],
+                    "example": "fair"
+                },
+                "shelf_location": {
+                    "type": "string",
+                    "example": "C1-02"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "available",
+                        "lost",
+                        "withdrawn"
+                    ],
+                    "example": "lost"
+                }
+            }
+        },
+        "models.UpdateMember": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jane.doe@example.com"
+                },
+                "firstname": {
+                    "type": "string",
+                    "example": "Jane Updated"
+                },
+                "lastname": {
+                    "type": "string",
+                    "example": "Doe Updated"
+                },
+                "max_loans": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+998901234567"
+                }
+            }
+        },
+        "models.UpdateReview": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 4
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Still good on a second read."
+                }
+            }
+        },
+        "models.UpdateSeries": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Updated description"
+                },
+                "series_name": {
+                    "type": "string",
+                    "example": "The Lord of the Rings Updated"
+                }
+            }
+        },
+        "models.UpdateWebhook": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "book.updated"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 16,
+                    "example": "a-new-shared-secret"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://search.example.com/hooks/catalog"
+                }
+            }
         }
     }
 }`
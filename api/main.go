@@ -2,13 +2,20 @@ package main
 
 import (
 	"github.com/saidakhmatov/catalog_of_books/api/docs"
+	"github.com/saidakhmatov/catalog_of_books/bus"
 
 	"fmt"
+	"log"
+	"time"
 	"github.com/saidakhmatov/catalog_of_books/config"
+	"github.com/saidakhmatov/catalog_of_books/graph"
 	"github.com/saidakhmatov/catalog_of_books/handler"
+	"github.com/saidakhmatov/catalog_of_books/helper"
 	
 
+	"github.com/saidakhmatov/catalog_of_books/storage/filesystem"
 	"github.com/saidakhmatov/catalog_of_books/storage/postgres"
+	"github.com/saidakhmatov/catalog_of_books/webhook"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
 
@@ -32,10 +39,47 @@ func main() {
 	)
 
 
-	strg := postgres.NewPostgres(str)
+	if err := helper.RegisterValidators(); err != nil {
+		log.Fatalf("Could not register validators: %v", err)
+	}
+
+	ids, err := helper.NewIDGenerator(cfg.IDStrategy)
+	if err != nil {
+		log.Fatalf("Could not create id generator: %v", err)
+	}
+
+	strg := postgres.NewPostgres(str, ids)
 	defer strg.CloseDB()
 
-	handler := handler.NewHandler(strg)
+	events := bus.New()
+
+	listener, err := postgres.NewListener(str, events)
+	if err != nil {
+		log.Fatalf("Could not listen for events: %v", err)
+	}
+	defer listener.Close()
+
+	go listener.Run()
+
+	schema, err := graph.NewSchema(strg, ids, graph.Limits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	})
+	if err != nil {
+		log.Fatalf("Could not build the GraphQL schema: %v", err)
+	}
+
+	handler := handler.NewHandler(strg, filesystem.NewFileSystem(cfg.BlobStoragePath), ids, events, schema, cfg)
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			if _, err := strg.HoldRepo().ExpireHolds(cfg.HoldPickupPeriod()); err != nil {
+				log.Println("Could not expire holds:", err)
+			}
+		}
+	}()
+
+	go webhook.NewDispatcher(strg.WebhookRepo(), cfg).Run(events.Subscribe(1).C)
 
 	switch cfg.Environment {
 	case "dev":
@@ -52,6 +96,7 @@ func main() {
 	api := r.Group("/api")
 
 	v1 := api.Group("/v1")
+	v1.Use(handler.ValidateIDParams)
 	{
 		
 		
@@ -59,9 +104,12 @@ func main() {
 		{
 			authors.POST("/", handler.CreateAuthor)
 			authors.GET("/", handler.GetAllAuthors)
+			authors.GET("/duplicates", handler.GetAuthorDuplicates)
 			authors.GET("/:id", handler.GetAuthor)
 			authors.PUT("/:id", handler.UpdateAuthor)
 			authors.DELETE("/:id", handler.DeleteAuthor)
+			authors.POST("/:id/merge", handler.MergeAuthors)
+			authors.GET("/:id/history", handler.GetAuthorHistory)
 		}
 
 		
@@ -73,6 +121,7 @@ func main() {
 			book_category.GET("/:id", handler.GetBookCategory)
 			book_category.PUT("/:id", handler.UpdateBookCategory)
 			book_category.DELETE("/:id", handler.DeleteBookCategory)
+			book_category.GET("/:id/history", handler.GetBookCategoryHistory)
 		}
 
 		
@@ -84,6 +133,86 @@ func main() {
 			books.GET("/:id", handler.GetBook)
 			books.PUT("/:id", handler.UpdateBook)
 			books.DELETE("/:id", handler.DeleteBook)
+			books.GET("/:id/history", handler.GetBookHistory)
+			books.POST("/:id/revert", handler.RevertBook)
+
+			books.PUT("/:id/cover", handler.UploadCover)
+			books.GET("/:id/cover", handler.GetCover)
+
+			books.POST("/:id/copies", handler.CreateCopy)
+			books.GET("/:id/copies", handler.GetAllCopies)
+			books.GET("/:id/copies/:copy_id", handler.GetCopy)
+			books.PUT("/:id/copies/:copy_id", handler.UpdateCopy)
+			books.DELETE("/:id/copies/:copy_id", handler.DeleteCopy)
+
+			books.POST("/:id/holds", handler.CreateHold)
+			books.GET("/:id/holds", handler.GetBookHolds)
+
+			books.POST("/:id/reviews", handler.CreateReview)
+			books.GET("/:id/reviews", handler.GetAllReviews)
+			books.GET("/:id/reviews/:review_id", handler.GetReview)
+			books.PUT("/:id/reviews/:review_id", handler.UpdateReview)
+			books.DELETE("/:id/reviews/:review_id", handler.DeleteReview)
+		}
+
+		copies := v1.Group("/copies")
+		{
+			copies.GET("/barcode/:barcode", handler.GetCopyByBarcode)
+		}
+
+		members := v1.Group("/members")
+		{
+			members.POST("/", handler.CreateMember)
+			members.GET("/", handler.GetAllMembers)
+			members.GET("/:id", handler.GetMember)
+			members.PUT("/:id", handler.UpdateMember)
+			members.DELETE("/:id", handler.DeleteMember)
+			members.GET("/:id/holds", handler.GetMemberHolds)
+			members.GET("/:id/fines", handler.GetMemberFines)
+			members.POST("/:id/fines", handler.CreateMemberFine)
+		}
+
+		loans := v1.Group("/loans")
+		{
+			loans.POST("/", handler.CreateLoan)
+			loans.GET("/", handler.GetAllLoans)
+			loans.GET("/:id", handler.GetLoan)
+			loans.POST("/:id/return", handler.ReturnLoan)
+			loans.GET("/:id/fine", handler.GetLoanFine)
+		}
+
+		holds := v1.Group("/holds")
+		{
+			holds.GET("/:id", handler.GetHold)
+			holds.POST("/:id/cancel", handler.CancelHold)
+		}
+
+		v1.GET("/events", handler.StreamEvents)
+		v1.GET("/sync", handler.Sync)
+		v1.POST("/graphql", handler.GraphQL)
+
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.POST("/", handler.CreateWebhook)
+			webhooks.GET("/", handler.GetAllWebhooks)
+			webhooks.GET("/:id", handler.GetWebhook)
+			webhooks.PUT("/:id", handler.UpdateWebhook)
+			webhooks.DELETE("/:id", handler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", handler.GetWebhookDeliveries)
+			webhooks.GET("/:id/deliveries/:delivery_id", handler.GetWebhookDelivery)
+			webhooks.POST("/:id/deliveries/:delivery_id/retry", handler.RetryWebhookDelivery)
+		}
+
+		series := v1.Group("/series")
+		{
+			series.POST("/", handler.CreateSeries)
+			series.GET("/", handler.GetAllSeries)
+			series.GET("/:id", handler.GetSeries)
+			series.PUT("/:id", handler.UpdateSeries)
+			series.DELETE("/:id", handler.DeleteSeries)
+			series.GET("/:id/books", handler.GetSeriesBooks)
+			series.PUT("/:id/books/:book_id", handler.AddBookToSeries)
+			series.DELETE("/:id/books/:book_id", handler.RemoveBookFromSeries)
 		}
 	}
 
@@ -0,0 +1,85 @@
+package bus
+
+import (
+	"sync"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// Bus fans catalog change notifications out to the components of this process that
+// subscribe to it, such as event streams and the webhook dispatcher.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+func New() *Bus {
+	return &Bus{subscribers: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives the notifications published after it was made on C.
+//
+// Publishing never waits for a subscriber. A notification that does not fit in a full
+// buffer is dropped and the subscriber later gets a notification with Resync set
+// instead, telling it to reload whatever it keeps, as after a lost database connection.
+type Subscription struct {
+	C <-chan models.Notification
+
+	bus    *Bus
+	mu     sync.Mutex
+	c      chan models.Notification
+	missed bool
+}
+
+// Subscribe returns a subscription buffering up to buffer notifications.
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	c := make(chan models.Notification, buffer)
+	s := &Subscription{C: c, bus: b, c: c}
+
+	b.mu.Lock()
+	b.subscribers[s] = struct{}{}
+	b.mu.Unlock()
+
+	return s
+}
+
+// Publish hands n to every subscriber.
+func (b *Bus) Publish(n models.Notification) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subscribers {
+		s.deliver(n)
+	}
+}
+
+// Close stops the subscription and closes C.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	delete(s.bus.subscribers, s)
+	s.bus.mu.Unlock()
+
+	s.mu.Lock()
+	close(s.c)
+	s.mu.Unlock()
+}
+
+func (s *Subscription) deliver(n models.Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.missed {
+		select {
+		case s.c <- models.Notification{Resync: true}:
+			s.missed = false
+		default:
+			return
+		}
+	}
+
+	select {
+	case s.c <- n:
+	default:
+		s.missed = true
+	}
+}
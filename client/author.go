@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateAuthor creates an author.
+func (c *Client) CreateAuthor(ctx context.Context, author models.CreateAuthor) (models.Author, error) {
+	var resp models.Author
+	err := c.call(ctx, request{method: http.MethodPost, path: "/authors/", body: author}, &resp)
+	return resp, err
+}
+
+// GetAllAuthors returns a page of the authors matching qP.
+func (c *Client) GetAllAuthors(ctx context.Context, qP models.ApplicationQueryParamModel) ([]models.Author, error) {
+	var resp []models.Author
+	err := c.call(ctx, request{method: http.MethodGet, path: "/authors/", query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetAuthorsByIDs returns the authors with the given ids, in order, and the ids that
+// matched no author. When fields are given only those are filled in.
+func (c *Client) GetAuthorsByIDs(ctx context.Context, ids []string, fields ...string) ([]models.Author, []string, error) {
+	var resp []models.Author
+	notFound, err := c.getBatch(ctx, "/authors/", ids, fields, &resp)
+	return resp, notFound, err
+}
+
+// GetAuthor returns the author with the given id or slug. When fields are given only
+// those are filled in.
+func (c *Client) GetAuthor(ctx context.Context, id string, fields ...string) (models.Author, error) {
+	var resp models.Author
+	err := c.call(ctx, request{method: http.MethodGet, path: path("authors", id), query: fieldsQuery(fields)}, &resp)
+	return resp, err
+}
+
+// GetAuthorAsOf returns the author with the given id or slug as it was at asOf.
+func (c *Client) GetAuthorAsOf(ctx context.Context, id string, asOf time.Time, fields ...string) (models.Author, error) {
+	q := pageQuery(nil, models.ApplicationQueryParamModel{Fields: fields, AsOf: asOf})
+
+	var resp models.Author
+	err := c.call(ctx, request{method: http.MethodGet, path: path("authors", id), query: q}, &resp)
+	return resp, err
+}
+
+// UpdateAuthor changes the given fields of an author and returns it.
+func (c *Client) UpdateAuthor(ctx context.Context, id string, author models.UpdateAuthor) (models.Author, error) {
+	var resp models.Author
+	err := c.call(ctx, request{method: http.MethodPut, path: path("authors", id), body: author}, &resp)
+	return resp, err
+}
+
+// DeleteAuthor deletes an author and returns the number of authors deleted.
+func (c *Client) DeleteAuthor(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("authors", id)}, &resp)
+	return resp, err
+}
+
+// GetAuthorDuplicates returns pairs of authors with similar names, best matches first.
+// A zero threshold or limit leaves the server default.
+func (c *Client) GetAuthorDuplicates(ctx context.Context, threshold float64, limit int) ([]models.AuthorDuplicate, error) {
+	q := pageQuery(nil, models.ApplicationQueryParamModel{Limit: limit})
+	if threshold > 0 {
+		q.Set("threshold", strconv.FormatFloat(threshold, 'f', -1, 64))
+	}
+
+	var resp []models.AuthorDuplicate
+	err := c.call(ctx, request{method: http.MethodGet, path: "/authors/duplicates", query: q}, &resp)
+	return resp, err
+}
+
+// MergeAuthors moves the books of the source authors to the author with the given id and
+// deletes the sources.
+func (c *Client) MergeAuthors(ctx context.Context, id string, merge models.MergeAuthors) (models.AuthorMerge, error) {
+	var resp models.AuthorMerge
+	err := c.call(ctx, request{method: http.MethodPost, path: path("authors", id, "merge"), body: merge}, &resp)
+	return resp, err
+}
+
+// GetAuthorHistory returns a page of the changes of an author, newest first.
+func (c *Client) GetAuthorHistory(ctx context.Context, id string, qP models.ApplicationQueryParamModel) ([]models.AuditEntry, error) {
+	var resp []models.AuditEntry
+	err := c.call(ctx, request{method: http.MethodGet, path: path("authors", id, "history"), query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
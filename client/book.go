@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateBook creates a book.
+func (c *Client) CreateBook(ctx context.Context, book models.CreateBook) (models.Book, error) {
+	var resp models.Book
+	err := c.call(ctx, request{method: http.MethodPost, path: "/books/", body: book}, &resp)
+	return resp, err
+}
+
+// GetAllBooks returns a page of the books matching the filters of qP.
+func (c *Client) GetAllBooks(ctx context.Context, qP models.BookQueryParamModel) ([]models.Book, error) {
+	q := pageQuery(nil, qP.ApplicationQueryParamModel)
+	if len(qP.Language) > 0 {
+		q.Set("language", qP.Language)
+	}
+	if len(qP.Format) > 0 {
+		q.Set("format", qP.Format)
+	}
+	if qP.YearFrom != 0 {
+		q.Set("year_from", strconv.Itoa(qP.YearFrom))
+	}
+	if qP.YearTo != 0 {
+		q.Set("year_to", strconv.Itoa(qP.YearTo))
+	}
+	if len(qP.Sort) > 0 {
+		q.Set("sort", qP.Sort)
+	}
+
+	var resp []models.Book
+	err := c.call(ctx, request{method: http.MethodGet, path: "/books/", query: q}, &resp)
+	return resp, err
+}
+
+// GetBooksByIDs returns the books with the given ids, in order, and the ids that matched
+// no book. When fields are given only those are filled in.
+func (c *Client) GetBooksByIDs(ctx context.Context, ids []string, fields ...string) ([]models.Book, []string, error) {
+	var resp []models.Book
+	notFound, err := c.getBatch(ctx, "/books/", ids, fields, &resp)
+	return resp, notFound, err
+}
+
+// GetBook returns the book with the given id or slug. When fields are given only those
+// are filled in.
+func (c *Client) GetBook(ctx context.Context, id string, fields ...string) (models.Book, error) {
+	var resp models.Book
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", id), query: fieldsQuery(fields)}, &resp)
+	return resp, err
+}
+
+// GetBookAsOf returns the book with the given id or slug as it was at asOf.
+func (c *Client) GetBookAsOf(ctx context.Context, id string, asOf time.Time, fields ...string) (models.Book, error) {
+	q := pageQuery(nil, models.ApplicationQueryParamModel{Fields: fields, AsOf: asOf})
+
+	var resp models.Book
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", id), query: q}, &resp)
+	return resp, err
+}
+
+// UpdateBook changes the given fields of a book and returns it.
+func (c *Client) UpdateBook(ctx context.Context, id string, book models.UpdateBook) (models.Book, error) {
+	var resp models.Book
+	err := c.call(ctx, request{method: http.MethodPut, path: path("books", id), body: book}, &resp)
+	return resp, err
+}
+
+// DeleteBook deletes a book and returns the number of books deleted.
+func (c *Client) DeleteBook(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("books", id)}, &resp)
+	return resp, err
+}
+
+// GetBookHistory returns a page of the changes of a book, newest first.
+func (c *Client) GetBookHistory(ctx context.Context, id string, qP models.ApplicationQueryParamModel) ([]models.AuditEntry, error) {
+	var resp []models.AuditEntry
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", id, "history"), query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// RevertBook restores a book to a version from its history and returns it.
+func (c *Client) RevertBook(ctx context.Context, id string, version int) (models.Book, error) {
+	q := map[string][]string{"version": {strconv.Itoa(version)}}
+
+	var resp models.Book
+	err := c.call(ctx, request{method: http.MethodPost, path: path("books", id, "revert"), query: q}, &resp)
+	return resp, err
+}
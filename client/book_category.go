@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateBookCategory creates a book category.
+func (c *Client) CreateBookCategory(ctx context.Context, category models.CreateBookCategory) (models.BookCategory, error) {
+	var resp models.BookCategory
+	err := c.call(ctx, request{method: http.MethodPost, path: "/book_category/", body: category}, &resp)
+	return resp, err
+}
+
+// GetAllBookCategories returns a page of the book categories matching qP.
+func (c *Client) GetAllBookCategories(ctx context.Context, qP models.ApplicationQueryParamModel) ([]models.BookCategory, error) {
+	var resp []models.BookCategory
+	err := c.call(ctx, request{method: http.MethodGet, path: "/book_category/", query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetBookCategoriesByIDs returns the book categories with the given ids, in order, and
+// the ids that matched no category. When fields are given only those are filled in.
+func (c *Client) GetBookCategoriesByIDs(ctx context.Context, ids []string, fields ...string) ([]models.BookCategory, []string, error) {
+	var resp []models.BookCategory
+	notFound, err := c.getBatch(ctx, "/book_category/", ids, fields, &resp)
+	return resp, notFound, err
+}
+
+// GetBookCategory returns the book category with the given id or slug. When fields are
+// given only those are filled in.
+func (c *Client) GetBookCategory(ctx context.Context, id string, fields ...string) (models.BookCategory, error) {
+	var resp models.BookCategory
+	err := c.call(ctx, request{method: http.MethodGet, path: path("book_category", id), query: fieldsQuery(fields)}, &resp)
+	return resp, err
+}
+
+// GetBookCategoryAsOf returns the book category with the given id or slug as it was at
+// asOf.
+func (c *Client) GetBookCategoryAsOf(ctx context.Context, id string, asOf time.Time, fields ...string) (models.BookCategory, error) {
+	q := pageQuery(nil, models.ApplicationQueryParamModel{Fields: fields, AsOf: asOf})
+
+	var resp models.BookCategory
+	err := c.call(ctx, request{method: http.MethodGet, path: path("book_category", id), query: q}, &resp)
+	return resp, err
+}
+
+// UpdateBookCategory renames a book category and returns it.
+func (c *Client) UpdateBookCategory(ctx context.Context, id string, category models.UpdateBookCategory) (models.BookCategory, error) {
+	var resp models.BookCategory
+	err := c.call(ctx, request{method: http.MethodPut, path: path("book_category", id), body: category}, &resp)
+	return resp, err
+}
+
+// DeleteBookCategory deletes a book category and returns the number of categories
+// deleted.
+func (c *Client) DeleteBookCategory(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("book_category", id)}, &resp)
+	return resp, err
+}
+
+// GetBookCategoryHistory returns a page of the changes of a book category, newest first.
+func (c *Client) GetBookCategoryHistory(ctx context.Context, id string, qP models.ApplicationQueryParamModel) ([]models.AuditEntry, error) {
+	var resp []models.AuditEntry
+	err := c.call(ctx, request{method: http.MethodGet, path: path("book_category", id, "history"), query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
@@ -0,0 +1,293 @@
+// Package client is a typed Go client of the catalog API. It has a method for every
+// route, takes and returns the models types, and unwraps the response envelope.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// Config configures a Client. Only BaseURL is required.
+type Config struct {
+	// BaseURL is the root of the server, e.g. http://localhost:8080. The /api/v1 prefix
+	// is added by the client.
+	BaseURL string
+	// Timeout bounds each attempt of a request. Zero means 30 seconds.
+	Timeout time.Duration
+	// MaxRetries is how many times a failed idempotent request is retried: GET, PUT and
+	// DELETE requests that failed to connect or got a 5xx or 429 answer.
+	MaxRetries int
+	// RetryWait is the wait before the first retry, doubling after every further retry.
+	// Zero means 200 milliseconds.
+	RetryWait time.Duration
+	// Actor is sent as X-Actor and recorded in the history of the changes made.
+	Actor string
+	// Token is sent as a bearer token, for servers behind an authenticating proxy.
+	Token string
+	// HTTPClient sends the requests. Nil means a new http.Client.
+	HTTPClient *http.Client
+}
+
+// Client calls the catalog API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	cfg     Config
+	http    *http.Client
+}
+
+// New returns a client of the server at cfg.BaseURL.
+func New(cfg Config) (*Client, error) {
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("base url must be http or https, got %q", cfg.BaseURL)
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.RetryWait == 0 {
+		cfg.RetryWait = 200 * time.Millisecond
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/") + "/api/v1",
+		cfg:     cfg,
+		http:    httpClient,
+	}, nil
+}
+
+// request is one API call.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        interface{} // encoded as JSON unless it is rawBody
+	contentType string
+	header      http.Header
+	stream      bool // the response is read for as long as it lasts, without Timeout
+	badRequest  bool // a 400 answer is returned like a successful one, for the caller to decode
+}
+
+// rawBody is a request body sent as is.
+type rawBody []byte
+
+// envelope is the body every route but the event stream, covers and GraphQL answers with.
+type envelope struct {
+	Response struct {
+		Error   string
+		Message string
+		Data    json.RawMessage
+	} `json:"response"`
+}
+
+// call sends a request and decodes the data of the response envelope into out, unless
+// out is nil.
+func (c *Client) call(ctx context.Context, req request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("decoding %s %s response: %w", req.method, req.path, err)
+	}
+
+	if out == nil || len(env.Response.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Response.Data, out); err != nil {
+		return fmt.Errorf("decoding %s %s response: %w", req.method, req.path, err)
+	}
+
+	return nil
+}
+
+// send sends a request, retrying it if it is idempotent, and returns the successful
+// response. Unsuccessful responses are returned as *Error.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	var body []byte
+	switch b := req.body.(type) {
+	case nil:
+	case rawBody:
+		body = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.contentType = "application/json"
+	}
+
+	idempotent := req.method == http.MethodGet || req.method == http.MethodPut || req.method == http.MethodDelete
+	wait := c.cfg.RetryWait
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, req, body)
+		if err == nil {
+			return resp, nil
+		}
+
+		if !idempotent || attempt >= c.cfg.MaxRetries || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	var cancel context.CancelFunc
+	if req.stream {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+	}
+
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, reader)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	if len(req.contentType) > 0 {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if len(c.cfg.Actor) > 0 {
+		httpReq.Header.Set("X-Actor", c.cfg.Actor)
+	}
+	if len(c.cfg.Token) > 0 {
+		httpReq.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 || req.badRequest && resp.StatusCode == http.StatusBadRequest {
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+
+	defer cancel()
+	defer resp.Body.Close()
+
+	return nil, responseError(resp)
+}
+
+// cancelOnClose releases the timeout of a request when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// pageQuery adds the search, paging, fields and as_of parameters of a list.
+func pageQuery(q url.Values, p models.ApplicationQueryParamModel) url.Values {
+	if q == nil {
+		q = url.Values{}
+	}
+	if len(p.Search) > 0 {
+		q.Set("search", p.Search)
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if len(p.Fields) > 0 {
+		q.Set("fields", strings.Join(p.Fields, ","))
+	}
+	if !p.AsOf.IsZero() {
+		q.Set("as_of", p.AsOf.Format(time.RFC3339Nano))
+	}
+	return q
+}
+
+// fieldsQuery selects fields of a single entity.
+func fieldsQuery(fields []string) url.Values {
+	return pageQuery(nil, models.ApplicationQueryParamModel{Fields: fields})
+}
+
+// idsQuery asks a list route for the entities with the given ids.
+func idsQuery(ids []string, fields []string) url.Values {
+	q := fieldsQuery(fields)
+	q.Set("ids", strings.Join(ids, ","))
+	return q
+}
+
+// batch is the data of a read by ids.
+type batch struct {
+	Items    json.RawMessage `json:"items"`
+	NotFound []string        `json:"not_found"`
+}
+
+// path joins path segments, escaping each.
+func path(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String()
+}
+
+// getBatch reads the entities with the given ids from a list route into items and returns
+// the ids that matched nothing.
+func (c *Client) getBatch(ctx context.Context, listPath string, ids []string, fields []string, items interface{}) ([]string, error) {
+	var resp batch
+	if err := c.call(ctx, request{method: http.MethodGet, path: listPath, query: idsQuery(ids, fields)}, &resp); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(resp.Items, items); err != nil {
+		return nil, fmt.Errorf("decoding GET %s response: %w", listPath, err)
+	}
+
+	return resp.NotFound, nil
+}
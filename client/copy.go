@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateCopy adds a physical copy of a book and returns its id.
+func (c *Client) CreateCopy(ctx context.Context, bookID string, copy models.CreateCopy) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: path("books", bookID, "copies"), body: copy}, &resp)
+	return resp, err
+}
+
+// GetAllCopies returns a page of the copies of a book.
+func (c *Client) GetAllCopies(ctx context.Context, bookID string, qP models.ApplicationQueryParamModel) ([]models.Copy, error) {
+	var resp []models.Copy
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", bookID, "copies"), query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetCopy returns a copy of a book.
+func (c *Client) GetCopy(ctx context.Context, bookID, id string) (models.Copy, error) {
+	var resp models.Copy
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", bookID, "copies", id)}, &resp)
+	return resp, err
+}
+
+// GetCopyByBarcode returns the copy with the given barcode.
+func (c *Client) GetCopyByBarcode(ctx context.Context, barcode string) (models.Copy, error) {
+	var resp models.Copy
+	err := c.call(ctx, request{method: http.MethodGet, path: path("copies", "barcode", barcode)}, &resp)
+	return resp, err
+}
+
+// UpdateCopy changes the given fields of a copy and returns the number of copies updated.
+func (c *Client) UpdateCopy(ctx context.Context, bookID, id string, copy models.UpdateCopy) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPut, path: path("books", bookID, "copies", id), body: copy}, &resp)
+	return resp, err
+}
+
+// DeleteCopy deletes a copy and returns the number of copies deleted.
+func (c *Client) DeleteCopy(ctx context.Context, bookID, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("books", bookID, "copies", id)}, &resp)
+	return resp, err
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// UploadCover sets the cover of a book to a JPEG or PNG image.
+func (c *Client) UploadCover(ctx context.Context, bookID, filename string, image []byte) (models.Cover, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	part, err := form.CreateFormFile("cover", filename)
+	if err != nil {
+		return models.Cover{}, err
+	}
+	if _, err := part.Write(image); err != nil {
+		return models.Cover{}, err
+	}
+	if err := form.Close(); err != nil {
+		return models.Cover{}, err
+	}
+
+	req := request{
+		method:      http.MethodPut,
+		path:        path("books", bookID, "cover"),
+		body:        rawBody(body.Bytes()),
+		contentType: form.FormDataContentType(),
+	}
+
+	var resp models.Cover
+	err = c.call(ctx, req, &resp)
+	return resp, err
+}
+
+// GetCover returns the cover of a book in one of the models.CoverSize sizes, and its
+// content type. An empty size means the original.
+func (c *Client) GetCover(ctx context.Context, bookID, size string) ([]byte, string, error) {
+	var q url.Values
+	if len(size) > 0 {
+		q = url.Values{"size": {size}}
+	}
+
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: path("books", bookID, "cover"), query: q})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, resp.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// The errors the server answers with, by status code. Use errors.Is to tell them apart:
+//
+//	if errors.Is(err, client.ErrNotFound) { ... }
+var (
+	ErrBadRequest           = errors.New("bad request")
+	ErrNotFound             = errors.New("not found")
+	ErrPayloadTooLarge      = errors.New("payload too large")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrInternal             = errors.New("internal server error")
+)
+
+// statusErrors maps the status codes the server uses to their errors.
+var statusErrors = map[int]error{
+	http.StatusBadRequest:            ErrBadRequest,
+	http.StatusNotFound:              ErrNotFound,
+	http.StatusRequestEntityTooLarge: ErrPayloadTooLarge,
+	http.StatusUnsupportedMediaType:  ErrUnsupportedMediaType,
+	http.StatusInternalServerError:   ErrInternal,
+}
+
+// Error is an unsuccessful response. Message and Detail are the Message and Error of the
+// response envelope, when it has one.
+type Error struct {
+	StatusCode int
+	Message    string
+	Detail     string
+}
+
+func (e *Error) Error() string {
+	switch {
+	case len(e.Message) > 0 && len(e.Detail) > 0:
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Message, e.Detail)
+	case len(e.Detail) > 0:
+		return fmt.Sprintf("%d %s", e.StatusCode, e.Detail)
+	case len(e.Message) > 0:
+		return fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports whether target is the error of the status code of e.
+func (e *Error) Is(target error) bool {
+	return statusErrors[e.StatusCode] == target
+}
+
+// responseError reads the error of an unsuccessful response.
+func responseError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return e
+	}
+
+	var env envelope
+	if json.Unmarshal(data, &env) == nil {
+		e.Message = env.Response.Message
+		e.Detail = env.Response.Error
+	}
+
+	return e
+}
+
+// retryable reports whether a failed request may succeed if sent again.
+func retryable(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
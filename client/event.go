@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// StreamEvents follows the change feed and calls handle with every event and its id, in
+// order, until ctx is done, the stream ends or handle returns an error. Only events of
+// the given entities are streamed, or of all when none are given. A lastEventID of 0
+// starts at new events; otherwise the stream resumes after that event, so passing the id
+// of the last event handled continues where a previous stream stopped.
+func (c *Client) StreamEvents(ctx context.Context, entities []string, lastEventID int64, handle func(id int64, event models.Event) error) error {
+	q := url.Values{}
+	if len(entities) > 0 {
+		q.Set("entity", strings.Join(entities, ","))
+	}
+
+	header := http.Header{}
+	if lastEventID > 0 {
+		header.Set("Last-Event-ID", strconv.FormatInt(lastEventID, 10))
+	}
+
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/events", query: q, header: header, stream: true})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var id, data string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if len(line) == 0 {
+			if len(data) > 0 {
+				if err := dispatchEvent(id, data, handle); err != nil {
+					return err
+				}
+			}
+			id, data = "", ""
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "id":
+			id = value
+		case "data":
+			if len(data) > 0 {
+				data += "\n"
+			}
+			data += value
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
+
+// dispatchEvent decodes one event of the stream and hands it to handle.
+func dispatchEvent(id, data string, handle func(id int64, event models.Event) error) error {
+	eventID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid event id %q", id)
+	}
+
+	var event models.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return fmt.Errorf("decoding event %d: %w", eventID, err)
+	}
+
+	return handle(eventID, event)
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// GetMemberFines returns the fine balance of a member and a page of its entries.
+func (c *Client) GetMemberFines(ctx context.Context, memberID string, qP models.ApplicationQueryParamModel) (models.FineAccount, error) {
+	var resp models.FineAccount
+	err := c.call(ctx, request{method: http.MethodGet, path: path("members", memberID, "fines"), query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// CreateMemberFine records a payment or waiver for a member and returns its id.
+func (c *Client) CreateMemberFine(ctx context.Context, memberID string, fine models.CreateFine) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: path("members", memberID, "fines"), body: fine}, &resp)
+	return resp, err
+}
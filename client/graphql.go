@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// GraphQLRequest is a GraphQL operation with its variables.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLError holds the errors of a GraphQL request. Whatever data was resolved despite
+// them has still been decoded.
+type GraphQLError struct {
+	Errors []*gqlerrors.QueryError
+}
+
+func (e *GraphQLError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Message
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL runs a GraphQL operation and decodes its data into data, unless it is nil.
+// Errors of the operation are returned as *GraphQLError.
+func (c *Client) GraphQL(ctx context.Context, params GraphQLRequest, data interface{}) error {
+	resp, err := c.send(ctx, request{method: http.MethodPost, path: "/graphql", body: params, badRequest: true})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage         `json:"data"`
+		Errors []*gqlerrors.QueryError `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decoding POST /graphql response: %w", err)
+	}
+
+	if data != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			return fmt.Errorf("decoding POST /graphql response: %w", err)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return &GraphQLError{Errors: result.Errors}
+	}
+
+	return nil
+}
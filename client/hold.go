@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateHold places a hold on a book for a member and returns its id.
+func (c *Client) CreateHold(ctx context.Context, bookID string, hold models.CreateHold) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: path("books", bookID, "holds"), body: hold}, &resp)
+	return resp, err
+}
+
+// GetBookHolds returns the holds on a book.
+func (c *Client) GetBookHolds(ctx context.Context, bookID string) ([]models.Hold, error) {
+	var resp []models.Hold
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", bookID, "holds")}, &resp)
+	return resp, err
+}
+
+// GetMemberHolds returns the holds of a member.
+func (c *Client) GetMemberHolds(ctx context.Context, memberID string) ([]models.Hold, error) {
+	var resp []models.Hold
+	err := c.call(ctx, request{method: http.MethodGet, path: path("members", memberID, "holds")}, &resp)
+	return resp, err
+}
+
+// GetHold returns a hold.
+func (c *Client) GetHold(ctx context.Context, id string) (models.Hold, error) {
+	var resp models.Hold
+	err := c.call(ctx, request{method: http.MethodGet, path: path("holds", id)}, &resp)
+	return resp, err
+}
+
+// CancelHold cancels a hold and returns the number of holds cancelled.
+func (c *Client) CancelHold(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPost, path: path("holds", id, "cancel")}, &resp)
+	return resp, err
+}
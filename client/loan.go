@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateLoan checks a copy out to a member and returns the loan id.
+func (c *Client) CreateLoan(ctx context.Context, loan models.CreateLoan) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/loans/", body: loan}, &resp)
+	return resp, err
+}
+
+// GetAllLoans returns a page of the loans matching the filters of qP.
+func (c *Client) GetAllLoans(ctx context.Context, qP models.LoanQueryParamModel) ([]models.Loan, error) {
+	q := pageQuery(nil, qP.ApplicationQueryParamModel)
+	if len(qP.MemberID) > 0 {
+		q.Set("member_id", qP.MemberID)
+	}
+	if qP.ActiveOnly {
+		q.Set("active", strconv.FormatBool(qP.ActiveOnly))
+	}
+
+	var resp []models.Loan
+	err := c.call(ctx, request{method: http.MethodGet, path: "/loans/", query: q}, &resp)
+	return resp, err
+}
+
+// GetLoan returns a loan.
+func (c *Client) GetLoan(ctx context.Context, id string) (models.Loan, error) {
+	var resp models.Loan
+	err := c.call(ctx, request{method: http.MethodGet, path: path("loans", id)}, &resp)
+	return resp, err
+}
+
+// ReturnLoan checks a loaned copy back in and returns the closed loan.
+func (c *Client) ReturnLoan(ctx context.Context, id string) (models.Loan, error) {
+	var resp models.Loan
+	err := c.call(ctx, request{method: http.MethodPost, path: path("loans", id, "return")}, &resp)
+	return resp, err
+}
+
+// GetLoanFine returns the fine a loan has accrued so far.
+func (c *Client) GetLoanFine(ctx context.Context, id string) (models.AccruedFine, error) {
+	var resp models.AccruedFine
+	err := c.call(ctx, request{method: http.MethodGet, path: path("loans", id, "fine")}, &resp)
+	return resp, err
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateMember registers a member and returns its id.
+func (c *Client) CreateMember(ctx context.Context, member models.CreateMember) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/members/", body: member}, &resp)
+	return resp, err
+}
+
+// GetAllMembers returns a page of the members matching qP.
+func (c *Client) GetAllMembers(ctx context.Context, qP models.ApplicationQueryParamModel) ([]models.Member, error) {
+	var resp []models.Member
+	err := c.call(ctx, request{method: http.MethodGet, path: "/members/", query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetMember returns a member.
+func (c *Client) GetMember(ctx context.Context, id string) (models.Member, error) {
+	var resp models.Member
+	err := c.call(ctx, request{method: http.MethodGet, path: path("members", id)}, &resp)
+	return resp, err
+}
+
+// UpdateMember changes the given fields of a member and returns the number of members
+// updated.
+func (c *Client) UpdateMember(ctx context.Context, id string, member models.UpdateMember) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPut, path: path("members", id), body: member}, &resp)
+	return resp, err
+}
+
+// DeleteMember deletes a member and returns the number of members deleted.
+func (c *Client) DeleteMember(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("members", id)}, &resp)
+	return resp, err
+}
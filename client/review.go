@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateReview adds a review of a book and returns its id.
+func (c *Client) CreateReview(ctx context.Context, bookID string, review models.CreateReview) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: path("books", bookID, "reviews"), body: review}, &resp)
+	return resp, err
+}
+
+// GetAllReviews returns a page of the reviews of a book.
+func (c *Client) GetAllReviews(ctx context.Context, bookID string, qP models.ApplicationQueryParamModel) ([]models.Review, error) {
+	var resp []models.Review
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", bookID, "reviews"), query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetReview returns a review of a book.
+func (c *Client) GetReview(ctx context.Context, bookID, id string) (models.Review, error) {
+	var resp models.Review
+	err := c.call(ctx, request{method: http.MethodGet, path: path("books", bookID, "reviews", id)}, &resp)
+	return resp, err
+}
+
+// UpdateReview changes the given fields of a review and returns the number of reviews
+// updated.
+func (c *Client) UpdateReview(ctx context.Context, bookID, id string, review models.UpdateReview) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPut, path: path("books", bookID, "reviews", id), body: review}, &resp)
+	return resp, err
+}
+
+// DeleteReview deletes a review and returns the number of reviews deleted.
+func (c *Client) DeleteReview(ctx context.Context, bookID, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("books", bookID, "reviews", id)}, &resp)
+	return resp, err
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateSeries creates a series and returns its id.
+func (c *Client) CreateSeries(ctx context.Context, series models.CreateSeries) (string, error) {
+	var resp string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/series/", body: series}, &resp)
+	return resp, err
+}
+
+// GetAllSeries returns a page of the series matching qP.
+func (c *Client) GetAllSeries(ctx context.Context, qP models.ApplicationQueryParamModel) ([]models.Series, error) {
+	var resp []models.Series
+	err := c.call(ctx, request{method: http.MethodGet, path: "/series/", query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetSeries returns a series.
+func (c *Client) GetSeries(ctx context.Context, id string) (models.Series, error) {
+	var resp models.Series
+	err := c.call(ctx, request{method: http.MethodGet, path: path("series", id)}, &resp)
+	return resp, err
+}
+
+// UpdateSeries changes the given fields of a series and returns the number of series
+// updated.
+func (c *Client) UpdateSeries(ctx context.Context, id string, series models.UpdateSeries) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPut, path: path("series", id), body: series}, &resp)
+	return resp, err
+}
+
+// DeleteSeries deletes a series and returns the number of series deleted.
+func (c *Client) DeleteSeries(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("series", id)}, &resp)
+	return resp, err
+}
+
+// GetSeriesBooks returns the books of a series in reading order.
+func (c *Client) GetSeriesBooks(ctx context.Context, id string) ([]models.Book, error) {
+	var resp []models.Book
+	err := c.call(ctx, request{method: http.MethodGet, path: path("series", id, "books")}, &resp)
+	return resp, err
+}
+
+// AddBookToSeries puts a book into a series at the given position, or moves it there, and
+// returns the number of books changed.
+func (c *Client) AddBookToSeries(ctx context.Context, id, bookID string, membership models.SeriesMembership) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPut, path: path("series", id, "books", bookID), body: membership}, &resp)
+	return resp, err
+}
+
+// RemoveBookFromSeries takes a book out of a series and returns the number of books
+// changed.
+func (c *Client) RemoveBookFromSeries(ctx context.Context, id, bookID string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("series", id, "books", bookID)}, &resp)
+	return resp, err
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// Sync returns the entities changed since a sync token, with tombstones for the deleted
+// ones. An empty token starts from the beginning; pass the NextToken of the page to the
+// next call. A zero limit leaves the server default.
+func (c *Client) Sync(ctx context.Context, since string, limit int) (models.SyncPage, error) {
+	q := url.Values{}
+	if len(since) > 0 {
+		q.Set("since", since)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+
+	var resp models.SyncPage
+	err := c.call(ctx, request{method: http.MethodGet, path: "/sync", query: q}, &resp)
+	return resp, err
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// CreateWebhook subscribes a URL to catalog events. The returned webhook carries the
+// signing secret, which later reads leave out.
+func (c *Client) CreateWebhook(ctx context.Context, webhook models.CreateWebhook) (models.Webhook, error) {
+	var resp models.Webhook
+	err := c.call(ctx, request{method: http.MethodPost, path: "/webhooks/", body: webhook}, &resp)
+	return resp, err
+}
+
+// GetAllWebhooks returns a page of the webhooks matching qP.
+func (c *Client) GetAllWebhooks(ctx context.Context, qP models.ApplicationQueryParamModel) ([]models.Webhook, error) {
+	var resp []models.Webhook
+	err := c.call(ctx, request{method: http.MethodGet, path: "/webhooks/", query: pageQuery(nil, qP)}, &resp)
+	return resp, err
+}
+
+// GetWebhook returns a webhook.
+func (c *Client) GetWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	var resp models.Webhook
+	err := c.call(ctx, request{method: http.MethodGet, path: path("webhooks", id)}, &resp)
+	return resp, err
+}
+
+// UpdateWebhook changes the given fields of a webhook and returns it.
+func (c *Client) UpdateWebhook(ctx context.Context, id string, webhook models.UpdateWebhook) (models.Webhook, error) {
+	var resp models.Webhook
+	err := c.call(ctx, request{method: http.MethodPut, path: path("webhooks", id), body: webhook}, &resp)
+	return resp, err
+}
+
+// DeleteWebhook deletes a webhook and returns the number of webhooks deleted.
+func (c *Client) DeleteWebhook(ctx context.Context, id string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodDelete, path: path("webhooks", id)}, &resp)
+	return resp, err
+}
+
+// GetWebhookDeliveries returns a page of the deliveries of a webhook, optionally only
+// those in qP.Status.
+func (c *Client) GetWebhookDeliveries(ctx context.Context, id string, qP models.WebhookDeliveryQueryParamModel) ([]models.WebhookDelivery, error) {
+	q := pageQuery(nil, qP.ApplicationQueryParamModel)
+	if len(qP.Status) > 0 {
+		q.Set("status", qP.Status)
+	}
+
+	var resp []models.WebhookDelivery
+	err := c.call(ctx, request{method: http.MethodGet, path: path("webhooks", id, "deliveries"), query: q}, &resp)
+	return resp, err
+}
+
+// GetWebhookDelivery returns a delivery of a webhook with its attempt log.
+func (c *Client) GetWebhookDelivery(ctx context.Context, id, deliveryID string) (models.WebhookDelivery, error) {
+	var resp models.WebhookDelivery
+	err := c.call(ctx, request{method: http.MethodGet, path: path("webhooks", id, "deliveries", deliveryID)}, &resp)
+	return resp, err
+}
+
+// RetryWebhookDelivery schedules a delivery to be attempted again right away and returns
+// the number of deliveries rescheduled.
+func (c *Client) RetryWebhookDelivery(ctx context.Context, id, deliveryID string) (int64, error) {
+	var resp int64
+	err := c.call(ctx, request{method: http.MethodPost, path: path("webhooks", id, "deliveries", deliveryID, "retry")}, &resp)
+	return resp, err
+}
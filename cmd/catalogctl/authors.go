@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"github.com/saidakhmatov/catalog_of_books/client"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+var authors = resource{
+	name:    "authors",
+	columns: []string{"id", "display_name", "nationality", "birth_date", "death_date"},
+
+	listFlags: pageLister(func(ctx context.Context, c *client.Client, page models.ApplicationQueryParamModel) (interface{}, error) {
+		return c.GetAllAuthors(ctx, page)
+	}),
+
+	get: func(ctx context.Context, c *client.Client, id string, fields []string) (interface{}, error) {
+		return c.GetAuthor(ctx, id, fields...)
+	},
+
+	create: func(ctx context.Context, c *client.Client, input string) (interface{}, error) {
+		var author models.CreateAuthor
+		if err := readInput(input, &author); err != nil {
+			return nil, err
+		}
+		return c.CreateAuthor(ctx, author)
+	},
+
+	update: func(ctx context.Context, c *client.Client, id, input string) (interface{}, error) {
+		var author models.UpdateAuthor
+		if err := readInput(input, &author); err != nil {
+			return nil, err
+		}
+		return c.UpdateAuthor(ctx, id, author)
+	},
+
+	delete: func(ctx context.Context, c *client.Client, id string) (int64, error) {
+		return c.DeleteAuthor(ctx, id)
+	},
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/saidakhmatov/catalog_of_books/client"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+var books = resource{
+	name:    "books",
+	columns: []string{"id", "book_name", "author_id", "category_id", "publication_year", "format", "language"},
+
+	listFlags: func(fs *flag.FlagSet) lister {
+		var qP models.BookQueryParamModel
+		fs.StringVar(&qP.Language, "language", "", "only books in this ISO 639-1 language")
+		fs.StringVar(&qP.Format, "format", "", "only books of this format: hardcover, paperback, ebook or audiobook")
+		fs.IntVar(&qP.YearFrom, "year-from", 0, "only books published in or after this year")
+		fs.IntVar(&qP.YearTo, "year-to", 0, "only books published in or before this year")
+		fs.StringVar(&qP.Sort, "sort", "", "sort field, prefix with - for descending, e.g. -average_rating")
+
+		return func(ctx context.Context, c *client.Client, page models.ApplicationQueryParamModel) (interface{}, error) {
+			qP.ApplicationQueryParamModel = page
+			return c.GetAllBooks(ctx, qP)
+		}
+	},
+
+	get: func(ctx context.Context, c *client.Client, id string, fields []string) (interface{}, error) {
+		return c.GetBook(ctx, id, fields...)
+	},
+
+	create: func(ctx context.Context, c *client.Client, input string) (interface{}, error) {
+		var book models.CreateBook
+		if err := readInput(input, &book); err != nil {
+			return nil, err
+		}
+		return c.CreateBook(ctx, book)
+	},
+
+	update: func(ctx context.Context, c *client.Client, id, input string) (interface{}, error) {
+		var book models.UpdateBook
+		if err := readInput(input, &book); err != nil {
+			return nil, err
+		}
+		return c.UpdateBook(ctx, id, book)
+	},
+
+	delete: func(ctx context.Context, c *client.Client, id string) (int64, error) {
+		return c.DeleteBook(ctx, id)
+	},
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"github.com/saidakhmatov/catalog_of_books/client"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+var categories = resource{
+	name:    "categories",
+	columns: []string{"id", "slug", "category_name"},
+
+	listFlags: pageLister(func(ctx context.Context, c *client.Client, page models.ApplicationQueryParamModel) (interface{}, error) {
+		return c.GetAllBookCategories(ctx, page)
+	}),
+
+	get: func(ctx context.Context, c *client.Client, id string, fields []string) (interface{}, error) {
+		return c.GetBookCategory(ctx, id, fields...)
+	},
+
+	create: func(ctx context.Context, c *client.Client, input string) (interface{}, error) {
+		var category models.CreateBookCategory
+		if err := readInput(input, &category); err != nil {
+			return nil, err
+		}
+		return c.CreateBookCategory(ctx, category)
+	},
+
+	update: func(ctx context.Context, c *client.Client, id, input string) (interface{}, error) {
+		var category models.UpdateBookCategory
+		if err := readInput(input, &category); err != nil {
+			return nil, err
+		}
+		return c.UpdateBookCategory(ctx, id, category)
+	},
+
+	delete: func(ctx context.Context, c *client.Client, id string) (int64, error) {
+		return c.DeleteBookCategory(ctx, id)
+	},
+}
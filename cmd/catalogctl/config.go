@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/client"
+	"gopkg.in/yaml.v2"
+)
+
+// settings tell catalogctl which server to talk to and as whom. They are read from the
+// config file, then the environment, then the flags, each overriding the one before.
+type settings struct {
+	Server  string        `yaml:"server"`
+	Actor   string        `yaml:"actor"`
+	Token   string        `yaml:"token"`
+	Timeout time.Duration `yaml:"timeout"`
+	Retries int           `yaml:"retries"`
+}
+
+// options are the flags every command takes.
+type options struct {
+	config string
+	server string
+	actor  string
+	output string
+}
+
+// register adds the flags to fs. Called for the flags of a command after those before
+// it have been parsed, it keeps their values as defaults.
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", o.config, "config file, default $CATALOGCTL_CONFIG or catalogctl/config.yaml in the user config directory")
+	fs.StringVar(&o.server, "server", o.server, "server address, e.g. http://localhost:8080")
+	fs.StringVar(&o.actor, "actor", o.actor, "who makes the changes, recorded in their history")
+	fs.StringVar(&o.output, "o", o.output, "output format: table, json or yaml")
+}
+
+// settings loads the settings, with the flags applied.
+func (o *options) settings() (settings, error) {
+	s := settings{
+		Server:  "http://localhost:8080",
+		Timeout: 30 * time.Second,
+		Retries: 2,
+	}
+
+	path, explicit := o.config, len(o.config) > 0
+	if !explicit {
+		path, explicit = os.LookupEnv("CATALOGCTL_CONFIG")
+	}
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err == nil {
+			path = filepath.Join(dir, "catalogctl", "config.yaml")
+		}
+	}
+
+	if len(path) > 0 {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.UnmarshalStrict(data, &s); err != nil {
+				return s, fmt.Errorf("reading %s: %w", path, err)
+			}
+		case explicit || !errors.Is(err, os.ErrNotExist):
+			return s, err
+		}
+	}
+
+	if v, ok := os.LookupEnv("CATALOG_SERVER"); ok {
+		s.Server = v
+	}
+	if v, ok := os.LookupEnv("CATALOG_ACTOR"); ok {
+		s.Actor = v
+	}
+	if v, ok := os.LookupEnv("CATALOG_TOKEN"); ok {
+		s.Token = v
+	}
+
+	if len(o.server) > 0 {
+		s.Server = o.server
+	}
+	if len(o.actor) > 0 {
+		s.Actor = o.actor
+	}
+
+	return s, nil
+}
+
+// client returns a client of the configured server.
+func (o *options) client() (*client.Client, error) {
+	s, err := o.settings()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(client.Config{
+		BaseURL:    s.Server,
+		Timeout:    s.Timeout,
+		MaxRetries: s.Retries,
+		Actor:      s.Actor,
+		Token:      s.Token,
+	})
+}
+
+// printer returns the printer of the chosen output format.
+func (o *options) printer() (printer, error) {
+	switch o.output {
+	case "", formatTable, formatJSON, formatYAML:
+		return printer{w: os.Stdout, format: o.output}, nil
+	default:
+		return printer{}, fmt.Errorf("unknown output format %q, must be table, json or yaml", o.output)
+	}
+}
@@ -0,0 +1,122 @@
+// Command catalogctl administers the catalog through its HTTP API. It lists, reads,
+// creates, updates and deletes books, authors and book categories, and exports and
+// imports the catalog as a whole.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: catalogctl [flags] <command> [arguments]
+
+Commands:
+  books list|get|create|update|delete       manage books
+  authors list|get|create|update|delete     manage authors
+  categories list|get|create|update|delete  manage book categories
+  export                                    write every category, author and book
+  import                                    create the categories, authors and books of an export
+
+Run catalogctl <command> -h for the arguments of a command.
+
+The server and credentials are read from the config file, then from CATALOG_SERVER,
+CATALOG_ACTOR and CATALOG_TOKEN, then from the flags.
+
+Flags:
+`
+
+// errUsage is returned for a command line that cannot be run. Its message has already
+// been printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:])
+	stop()
+
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "catalogctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("catalogctl", flag.ContinueOnError)
+	opts.register(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	switch args[0] {
+	case "books":
+		return runResource(ctx, &opts, books, args[1:])
+	case "authors":
+		return runResource(ctx, &opts, authors, args[1:])
+	case "categories":
+		return runResource(ctx, &opts, categories, args[1:])
+	case "export":
+		return runExport(ctx, &opts, args[1:])
+	case "import":
+		return runImport(ctx, &opts, args[1:])
+	case "help":
+		fs.SetOutput(os.Stdout)
+		fs.Usage()
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, run catalogctl -h for the commands", args[0])
+	}
+}
+
+// parseArgs parses the flags of a command, which may come before, between or after its
+// positional arguments, and checks that there are as many of those as names.
+func parseArgs(fs *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != len(names) {
+		fmt.Fprintf(fs.Output(), "usage: %s [flags]", fs.Name())
+		for _, name := range names {
+			fmt.Fprintf(fs.Output(), " <%s>", name)
+		}
+		fmt.Fprintln(fs.Output())
+		return nil, errUsage
+	}
+
+	return positional, nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// printer writes results in an output format. Lists are printed as a table with a row per
+// item, single results as a table with a row per field.
+type printer struct {
+	w      io.Writer
+	format string // table when empty
+}
+
+// print writes v. The columns select the fields shown in a table; all fields are shown
+// when there are none.
+func (p printer) print(v interface{}, columns []string) error {
+	switch p.format {
+	case formatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		ordered, err := ordered(v)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(ordered)
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(data)
+		return err
+	}
+
+	ordered, err := ordered(v)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+
+	switch ordered := ordered.(type) {
+	case []yaml.MapSlice:
+		if len(columns) == 0 && len(ordered) > 0 {
+			for _, item := range ordered[0] {
+				columns = append(columns, fmt.Sprint(item.Key))
+			}
+		}
+
+		headers := make([]string, len(columns))
+		for i, column := range columns {
+			headers[i] = strings.ToUpper(column)
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+		for _, row := range ordered {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = cell(lookup(row, column))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case yaml.MapSlice:
+		if len(columns) == 0 {
+			for _, item := range ordered {
+				columns = append(columns, fmt.Sprint(item.Key))
+			}
+		}
+
+		for _, column := range columns {
+			fmt.Fprintf(tw, "%s\t%s\n", column, cell(lookup(ordered, column)))
+		}
+	default:
+		fmt.Fprintln(tw, cell(ordered))
+	}
+
+	return tw.Flush()
+}
+
+// ordered converts v to what its JSON decodes to, keeping the order of the fields of
+// objects, so that YAML and tables show them in the order JSON does.
+func ordered(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is YAML, and yaml.v2 keeps the order of keys when decoding into MapSlice.
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		var list []yaml.MapSlice
+		err = yaml.Unmarshal(data, &list)
+		return list, err
+	case len(data) > 0 && data[0] == '{':
+		var object yaml.MapSlice
+		err = yaml.Unmarshal(data, &object)
+		return object, err
+	default:
+		var scalar interface{}
+		err = yaml.Unmarshal(data, &scalar)
+		return scalar, err
+	}
+}
+
+// lookup returns the value of a field of an object.
+func lookup(object yaml.MapSlice, key string) interface{} {
+	for _, item := range object {
+		if fmt.Sprint(item.Key) == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// cell formats a value for a table.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case yaml.MapSlice:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprintf("%v=%s", item.Key, cell(item.Value))
+		}
+		return strings.Join(parts, " ")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = cell(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// readInput decodes a JSON or YAML document from a file, or from stdin when path is "-",
+// into v. Fields v does not have are an error, to catch misspelt ones.
+func readInput(path string, v interface{}) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// YAML is decoded generically and passed on as JSON, so the json tags of the models
+	// apply to both.
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	data, err = json.Marshal(jsonable(doc))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// jsonable converts the maps yaml.v2 decodes to, which have interface{} keys, to maps
+// encoding/json can encode.
+func jsonable(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = jsonable(value)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonable(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/saidakhmatov/catalog_of_books/client"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// resource is an entity catalogctl lists, reads, creates, updates and deletes.
+type resource struct {
+	name    string   // as on the command line, e.g. books
+	columns []string // fields shown in a table of a list
+
+	// listFlags adds the filters particular to the resource to the flags of list and
+	// returns the lister that applies them.
+	listFlags func(fs *flag.FlagSet) lister
+
+	get    func(ctx context.Context, c *client.Client, id string, fields []string) (interface{}, error)
+	create func(ctx context.Context, c *client.Client, input string) (interface{}, error)
+	update func(ctx context.Context, c *client.Client, id, input string) (interface{}, error)
+	delete func(ctx context.Context, c *client.Client, id string) (int64, error)
+}
+
+// lister returns a page of a resource.
+type lister func(ctx context.Context, c *client.Client, page models.ApplicationQueryParamModel) (interface{}, error)
+
+func runResource(ctx context.Context, opts *options, r resource, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command, usage: catalogctl %s list|get|create|update|delete", r.name)
+	}
+
+	fs := flag.NewFlagSet("catalogctl "+r.name+" "+args[0], flag.ContinueOnError)
+	opts.register(fs)
+
+	var page models.ApplicationQueryParamModel
+	var fields, input string
+	var list lister
+	var names []string
+
+	switch args[0] {
+	case "list":
+		fs.StringVar(&page.Search, "search", "", "only those whose name contains this")
+		fs.IntVar(&page.Offset, "offset", 0, "number of results to skip")
+		fs.IntVar(&page.Limit, "limit", 0, "number of results, default 10")
+		fs.StringVar(&fields, "fields", "", "comma separated fields to return, e.g. id,slug")
+		list = r.listFlags(fs)
+	case "get":
+		fs.StringVar(&fields, "fields", "", "comma separated fields to return, e.g. id,slug")
+		names = []string{"id"}
+	case "create":
+		fs.StringVar(&input, "f", "-", "JSON or YAML file with the fields, - for stdin")
+	case "update":
+		fs.StringVar(&input, "f", "-", "JSON or YAML file with the fields to change, - for stdin")
+		names = []string{"id"}
+	case "delete":
+		names = []string{"id"}
+	default:
+		return fmt.Errorf("unknown command %q, usage: catalogctl %s list|get|create|update|delete", args[0], r.name)
+	}
+
+	positional, err := parseArgs(fs, args[1:], names...)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p, err := opts.printer()
+	if err != nil {
+		return err
+	}
+	c, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		page.Fields = strings.Split(fields, ",")
+	}
+
+	var res interface{}
+	switch args[0] {
+	case "list":
+		res, err = list(ctx, c, page)
+		if err != nil {
+			return err
+		}
+
+		columns := r.columns
+		if len(page.Fields) > 0 {
+			columns = page.Fields
+		}
+		return p.print(res, columns)
+	case "get":
+		res, err = r.get(ctx, c, positional[0], page.Fields)
+		if err != nil {
+			return err
+		}
+		return p.print(res, page.Fields)
+	case "create":
+		res, err = r.create(ctx, c, input)
+	case "update":
+		res, err = r.update(ctx, c, positional[0], input)
+	case "delete":
+		var n int64
+		n, err = r.delete(ctx, c, positional[0])
+		res = deleted{Deleted: n}
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.print(res, nil)
+}
+
+// deleted is the result of a delete.
+type deleted struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// pageLister lists a resource without filters of its own.
+func pageLister(list lister) func(fs *flag.FlagSet) lister {
+	return func(fs *flag.FlagSet) lister {
+		return list
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/saidakhmatov/catalog_of_books/client"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// exportPageSize is how many changes an export reads at once, the most the sync endpoint
+// returns.
+const exportPageSize = 100
+
+// catalog is the document export writes and import reads. Categories and authors come
+// before the books that refer to them.
+type catalog struct {
+	BookCategories []models.BookCategory `json:"book_categories"`
+	Authors        []models.Author       `json:"authors"`
+	Books          []models.Book         `json:"books"`
+}
+
+// imported counts what an import created.
+type imported struct {
+	BookCategories int `json:"book_categories"`
+	Authors        int `json:"authors"`
+	Books          int `json:"books"`
+}
+
+func runExport(ctx context.Context, opts *options, args []string) error {
+	fs := flag.NewFlagSet("catalogctl export", flag.ContinueOnError)
+	opts.register(fs)
+	output := fs.String("f", "-", "file to write, - for stdout")
+
+	if _, err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	// A catalog is a document, not a table; YAML is the default.
+	switch opts.output {
+	case "":
+		opts.output = formatYAML
+	case formatTable:
+		return errors.New("export writes json or yaml")
+	}
+
+	p, err := opts.printer()
+	if err != nil {
+		return err
+	}
+	c, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	doc, err := export(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		return p.print(doc, nil)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	p.w = f
+
+	if err := p.print(doc, nil); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// export reads every category, author and book. It follows the sync feed rather than
+// paging the lists, so that changes made while it runs neither hide nor repeat entities.
+func export(ctx context.Context, c *client.Client) (catalog, error) {
+	var order []string
+	latest := make(map[string]models.SyncChange)
+
+	token := ""
+	for {
+		page, err := c.Sync(ctx, token, exportPageSize)
+		if err != nil {
+			return catalog{}, err
+		}
+
+		// An entity changed during the export shows up again on a later page; its last
+		// state wins, in the place it was first seen.
+		for _, change := range page.Changes {
+			key := change.Entity + "/" + change.ID
+			if _, ok := latest[key]; !ok {
+				order = append(order, key)
+			}
+			latest[key] = change
+		}
+
+		token = page.NextToken
+		if !page.HasMore {
+			break
+		}
+	}
+
+	var doc catalog
+	for _, key := range order {
+		change := latest[key]
+		if change.Deleted {
+			continue
+		}
+
+		var err error
+		switch change.Entity {
+		case "book_category":
+			var category models.BookCategory
+			err = convert(change.Data, &category)
+			doc.BookCategories = append(doc.BookCategories, category)
+		case "author":
+			var author models.Author
+			err = convert(change.Data, &author)
+			doc.Authors = append(doc.Authors, author)
+		case "book":
+			var book models.Book
+			err = convert(change.Data, &book)
+			doc.Books = append(doc.Books, book)
+		}
+		if err != nil {
+			return catalog{}, fmt.Errorf("reading %s %s: %w", change.Entity, change.ID, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// convert decodes the generic data of a sync change into a model.
+func convert(data interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func runImport(ctx context.Context, opts *options, args []string) error {
+	fs := flag.NewFlagSet("catalogctl import", flag.ContinueOnError)
+	opts.register(fs)
+	input := fs.String("f", "-", "JSON or YAML export to read, - for stdin")
+
+	if _, err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	p, err := opts.printer()
+	if err != nil {
+		return err
+	}
+	c, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	var doc catalog
+	if err := readInput(*input, &doc); err != nil {
+		return err
+	}
+
+	res, err := importCatalog(ctx, c, doc)
+	if err != nil {
+		return fmt.Errorf("%w, after creating %d categories, %d authors and %d books", err, res.BookCategories, res.Authors, res.Books)
+	}
+
+	return p.print(res, nil)
+}
+
+// importCatalog creates the categories, authors and books of doc. They get new ids, and
+// the books refer to the new ids of the categories and authors imported with them; ids of
+// entities not in doc are kept, so books may refer to ones already on the server. Series
+// are not part of an export and are not set.
+func importCatalog(ctx context.Context, c *client.Client, doc catalog) (imported, error) {
+	var res imported
+	ids := make(map[string]string)
+
+	for _, category := range doc.BookCategories {
+		created, err := c.CreateBookCategory(ctx, models.CreateBookCategory{
+			CategoryName: category.CategoryName,
+		})
+		if err != nil {
+			return res, fmt.Errorf("importing category %q: %w", category.CategoryName, err)
+		}
+		ids[category.ID] = created.ID
+		res.BookCategories++
+	}
+
+	for _, author := range doc.Authors {
+		created, err := c.CreateAuthor(ctx, models.CreateAuthor{
+			Firstname:   author.Firstname,
+			Lastname:    author.Lastname,
+			BirthDate:   author.BirthDate,
+			DeathDate:   author.DeathDate,
+			Nationality: author.Nationality,
+			Biography:   author.Biography,
+			Aliases:     author.Aliases,
+		})
+		if err != nil {
+			return res, fmt.Errorf("importing author %q: %w", author.DisplayName, err)
+		}
+		ids[author.ID] = created.ID
+		res.Authors++
+	}
+
+	for _, book := range doc.Books {
+		_, err := c.CreateBook(ctx, models.CreateBook{
+			CategoryID:      mapID(ids, book.CategoryID),
+			AuthorID:        mapID(ids, book.AuthorID),
+			BookName:        book.BookName,
+			PublicationYear: book.PublicationYear,
+			Language:        book.Language,
+			PageCount:       book.PageCount,
+			Description:     book.Description,
+			Edition:         book.Edition,
+			Format:          book.Format,
+		})
+		if err != nil {
+			return res, fmt.Errorf("importing book %q: %w", book.BookName, err)
+		}
+		res.Books++
+	}
+
+	return res, nil
+}
+
+// mapID returns the id an imported entity was created with, or id itself when it was not
+// imported.
+func mapID(ids map[string]string, id string) string {
+	if created, ok := ids[id]; ok {
+		return created
+	}
+	return id
+}
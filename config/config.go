@@ -1,8 +1,13 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
@@ -25,6 +30,55 @@ type Config struct {
 
 	DefaultOffset string
 	DefaultLimit  string
+
+	LoanPeriodDays      int
+	LoanPeriodsByFormat map[string]int // book format -> loan period in days
+	DefaultMaxLoans     int
+	HoldPickupDays      int
+
+	FineRulesFile string
+	FineRules     FineRules
+
+	BlobStoragePath string
+	CoverMaxBytes   int64
+
+	IDStrategy string // uuidv4, uuidv7 or ulid
+
+	WebhookPollSeconds      int
+	WebhookTimeoutSeconds   int
+	WebhookMaxAttempts      int // failed deliveries are dead-lettered after this many attempts
+	WebhookRetryBaseSeconds int // the first retry waits this long, doubling after every attempt
+	WebhookRetryMaxSeconds  int
+	WebhookAllowPrivate     bool // deliver to loopback, link-local and private addresses too
+
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int // fields counted once per object they are expected on
+}
+
+// FineRule describes how overdue days of one material format are charged.
+// Amounts are in minor currency units.
+type FineRule struct {
+	DailyRate int64 `json:"daily_rate"`
+	GraceDays int   `json:"grace_days"`
+	MaxFine   int64 `json:"max_fine"` // 0 means uncapped
+}
+
+// FineRules is the overdue fine configuration read from FineRulesFile.
+type FineRules struct {
+	Default        FineRule            `json:"default"`
+	Formats        map[string]FineRule `json:"formats"`
+	ClosedWeekdays []string            `json:"closed_weekdays"` // e.g. "sunday"
+	ClosedDates    []string            `json:"closed_dates"`    // YYYY-MM-DD
+	MaxBalance     int64               `json:"max_balance"`     // members owing more cannot borrow
+}
+
+// Rule returns the fine rule for a book format, falling back to the default rule.
+func (r FineRules) Rule(format string) FineRule {
+	if rule, ok := r.Formats[format]; ok {
+		return rule
+	}
+
+	return r.Default
 }
 
 // Load ...
@@ -52,9 +106,187 @@ func Load() Config {
 	config.DefaultOffset = cast.ToString(getOrReturnDefaultValue("DEFAULT_OFFSET", "0"))
 	config.DefaultLimit = cast.ToString(getOrReturnDefaultValue("DEFAULT_LIMIT", "10"))
 
+	config.LoanPeriodDays = cast.ToInt(getOrReturnDefaultValue("LOAN_PERIOD_DAYS", 14))
+	config.LoanPeriodsByFormat = parseIntMap(cast.ToString(getOrReturnDefaultValue("LOAN_PERIODS_BY_FORMAT", "")))
+	config.DefaultMaxLoans = cast.ToInt(getOrReturnDefaultValue("DEFAULT_MAX_LOANS", 5))
+	config.HoldPickupDays = cast.ToInt(getOrReturnDefaultValue("HOLD_PICKUP_DAYS", 3))
+
+	config.FineRulesFile = cast.ToString(getOrReturnDefaultValue("FINE_RULES_FILE", "fine_rules.json"))
+	fineRules, err := loadFineRules(config.FineRulesFile)
+	if err != nil {
+		log.Fatalf("Could not load fine rules: %v", err)
+	}
+	config.FineRules = fineRules
+
+	config.BlobStoragePath = cast.ToString(getOrReturnDefaultValue("BLOB_STORAGE_PATH", "./data/blobs"))
+	config.CoverMaxBytes = cast.ToInt64(getOrReturnDefaultValue("COVER_MAX_BYTES", 5<<20))
+
+	config.IDStrategy = cast.ToString(getOrReturnDefaultValue("ID_STRATEGY", "uuidv7"))
+
+	config.WebhookPollSeconds = cast.ToInt(getOrReturnDefaultValue("WEBHOOK_POLL_SECONDS", 5))
+	config.WebhookTimeoutSeconds = cast.ToInt(getOrReturnDefaultValue("WEBHOOK_TIMEOUT_SECONDS", 10))
+	config.WebhookMaxAttempts = cast.ToInt(getOrReturnDefaultValue("WEBHOOK_MAX_ATTEMPTS", 8))
+	config.WebhookRetryBaseSeconds = cast.ToInt(getOrReturnDefaultValue("WEBHOOK_RETRY_BASE_SECONDS", 30))
+	config.WebhookRetryMaxSeconds = cast.ToInt(getOrReturnDefaultValue("WEBHOOK_RETRY_MAX_SECONDS", 3600))
+	config.WebhookAllowPrivate = cast.ToBool(getOrReturnDefaultValue("WEBHOOK_ALLOW_PRIVATE", false))
+
+	if err := config.validateWebhooks(); err != nil {
+		log.Fatalf("Invalid webhook config: %v", err)
+	}
+
+	config.GraphQLMaxDepth = cast.ToInt(getOrReturnDefaultValue("GRAPHQL_MAX_DEPTH", 8))
+	config.GraphQLMaxComplexity = cast.ToInt(getOrReturnDefaultValue("GRAPHQL_MAX_COMPLEXITY", 2000))
+
 	return config
 }
 
+// loadFineRules reads the fine rules file. Fines stay disabled when there is no file, but
+// a file that cannot be read or parsed is an error rather than fines silently turned off.
+func loadFineRules(path string) (FineRules, error) {
+	rules := FineRules{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Println("No fine rules file found, fines are disabled")
+		return rules, nil
+	}
+	if err != nil {
+		return rules, err
+	}
+
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return FineRules{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return FineRules{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+// validate checks the values fines are computed from: a negative amount would credit
+// members, and a closed day that is not understood would silently never match.
+func (r FineRules) validate() error {
+	if err := r.Default.validate("default"); err != nil {
+		return err
+	}
+
+	for format, rule := range r.Formats {
+		if err := rule.validate("formats." + format); err != nil {
+			return err
+		}
+	}
+
+	if r.MaxBalance < 0 {
+		return fmt.Errorf("max_balance must not be negative, got %d", r.MaxBalance)
+	}
+
+	for i, name := range r.ClosedWeekdays {
+		known := false
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			known = known || strings.EqualFold(wd.String(), name)
+		}
+		if !known {
+			return fmt.Errorf("closed_weekdays[%d] must be a day of the week, got %q", i, name)
+		}
+	}
+
+	for i, date := range r.ClosedDates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("closed_dates[%d] must be a YYYY-MM-DD date, got %q", i, date)
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the amounts and days of the rule named name are not negative.
+func (r FineRule) validate(name string) error {
+	switch {
+	case r.DailyRate < 0:
+		return fmt.Errorf("%s.daily_rate must not be negative, got %d", name, r.DailyRate)
+	case r.GraceDays < 0:
+		return fmt.Errorf("%s.grace_days must not be negative, got %d", name, r.GraceDays)
+	case r.MaxFine < 0:
+		return fmt.Errorf("%s.max_fine must not be negative, got %d", name, r.MaxFine)
+	}
+
+	return nil
+}
+
+// validateWebhooks checks the webhook settings the dispatcher cannot run without: a zero
+// poll interval panics its ticker, and a zero timeout or attempt count never delivers.
+func (c Config) validateWebhooks() error {
+	switch {
+	case c.WebhookPollSeconds < 1:
+		return fmt.Errorf("WEBHOOK_POLL_SECONDS must be at least 1, got %d", c.WebhookPollSeconds)
+	case c.WebhookTimeoutSeconds < 1:
+		return fmt.Errorf("WEBHOOK_TIMEOUT_SECONDS must be at least 1, got %d", c.WebhookTimeoutSeconds)
+	case c.WebhookMaxAttempts < 1:
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1, got %d", c.WebhookMaxAttempts)
+	case c.WebhookRetryBaseSeconds < 1:
+		return fmt.Errorf("WEBHOOK_RETRY_BASE_SECONDS must be at least 1, got %d", c.WebhookRetryBaseSeconds)
+	case c.WebhookRetryMaxSeconds < c.WebhookRetryBaseSeconds:
+		return fmt.Errorf("WEBHOOK_RETRY_MAX_SECONDS must be at least WEBHOOK_RETRY_BASE_SECONDS, got %d", c.WebhookRetryMaxSeconds)
+	}
+
+	return nil
+}
+
+// LoanPeriod returns how long a copy of a book in the given format may be borrowed.
+func (c Config) LoanPeriod(format string) time.Duration {
+	days, ok := c.LoanPeriodsByFormat[format]
+	if !ok {
+		days = c.LoanPeriodDays
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// HoldPickupPeriod returns how long a copy assigned to a hold waits for the member.
+func (c Config) HoldPickupPeriod() time.Duration {
+	return time.Duration(c.HoldPickupDays) * 24 * time.Hour
+}
+
+// WebhookRetryDelay returns how long to wait before retrying a delivery that has failed
+// attempts times: the base delay doubled after every further attempt, capped at the maximum.
+func (c Config) WebhookRetryDelay(attempts int) time.Duration {
+	delay := time.Duration(c.WebhookRetryBaseSeconds) * time.Second
+	max := time.Duration(c.WebhookRetryMaxSeconds) * time.Second
+
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		delay = max
+	}
+
+	return delay
+}
+
+// parseIntMap reads values like "ebook=7,audiobook=21", skipping malformed entries.
+func parseIntMap(value string) map[string]int {
+	result := make(map[string]int)
+
+	for _, pair := range strings.Split(value, ",") {
+		key, val, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+
+		n, err := cast.ToIntE(strings.TrimSpace(val))
+		if err != nil {
+			continue
+		}
+
+		result[strings.TrimSpace(key)] = n
+	}
+
+	return result
+}
+
 func getOrReturnDefaultValue(key string, defaultValue interface{}) interface{} {
 	val, exists := os.LookupEnv(key)
 
@@ -0,0 +1,220 @@
+package dedupe
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultThreshold is the similarity above which two authors are reported as likely duplicates.
+const DefaultThreshold = 0.9
+
+// Normalize lower-cases s, strips diacritics and punctuation and collapses whitespace,
+// so "J. R. R. Tolkien" becomes "j r r tolkien" and "Brontë" becomes "bronte".
+func Normalize(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(folded) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '\'' || r == '’':
+			// O'Brien and OBrien are the same name.
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Initials returns the initials of a given name. A short all-capital token such as "JRR"
+// is read as a run of initials rather than as a name.
+func Initials(givenName string) string {
+	var initials []rune
+	for _, token := range strings.FieldsFunc(givenName, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '.' || r == '-'
+	}) {
+		letters := []rune(Normalize(token))
+		if len(letters) == 0 {
+			continue
+		}
+		if len(letters) <= 4 && token == strings.ToUpper(token) && len(letters) > 1 {
+			initials = append(initials, letters...)
+			continue
+		}
+		initials = append(initials, letters[0])
+	}
+
+	return string(initials)
+}
+
+// Similarity scores how likely two authors are the same person, from 0 to 1. The family
+// names carry most of the weight; given names are compared in full when both are spelled
+// out and by their initials otherwise.
+func Similarity(a, b models.Author) float64 {
+	lastA, lastB := strings.ReplaceAll(Normalize(a.Lastname), " ", ""), strings.ReplaceAll(Normalize(b.Lastname), " ", "")
+	if lastA == "" || lastB == "" {
+		return 0
+	}
+
+	firstA, firstB := Normalize(a.Firstname), Normalize(b.Firstname)
+	if lastA == lastB && firstA == firstB {
+		return 1
+	}
+
+	var given float64
+	if isSpelledOut(a.Firstname) && isSpelledOut(b.Firstname) {
+		given = JaroWinkler(firstA, firstB)
+	} else {
+		given = initialsSimilarity(Initials(a.Firstname), Initials(b.Firstname))
+	}
+
+	return 0.6*JaroWinkler(lastA, lastB) + 0.4*given
+}
+
+// Candidates compares every pair of authors whose family names start with the same letter
+// and returns the pairs scoring at least threshold, best matches first.
+func Candidates(authors []models.Author, threshold float64) []models.AuthorDuplicate {
+	blocks := make(map[rune][]models.Author)
+	for _, author := range authors {
+		last := []rune(Normalize(author.Lastname))
+		if len(last) == 0 {
+			continue
+		}
+		blocks[last[0]] = append(blocks[last[0]], author)
+	}
+
+	resp := []models.AuthorDuplicate{}
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				score := Similarity(block[i], block[j])
+				if score < threshold {
+					continue
+				}
+
+				resp = append(resp, models.AuthorDuplicate{
+					Author:    block[i],
+					Duplicate: block[j],
+					Score:     float64(int(score*1000+0.5)) / 1000,
+				})
+			}
+		}
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Score != resp[j].Score {
+			return resp[i].Score > resp[j].Score
+		}
+		if resp[i].Author.ID != resp[j].Author.ID {
+			return resp[i].Author.ID < resp[j].Author.ID
+		}
+		return resp[i].Duplicate.ID < resp[j].Duplicate.ID
+	})
+
+	return resp
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 to 1.
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if matchedB[j] || ra[i] != rb[j] {
+				continue
+			}
+			matchedA[i], matchedB[j] = true, true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, k := 0, 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[k] {
+			k++
+		}
+		if ra[i] != rb[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, min(len(ra), len(rb))) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// isSpelledOut reports whether a given name starts with a full word rather than an initial.
+func isSpelledOut(givenName string) bool {
+	fields := strings.Fields(Normalize(givenName))
+	return len(fields) > 0 && len([]rune(fields[0])) > 1 && Initials(givenName) != fields[0]
+}
+
+func initialsSimilarity(a, b string) float64 {
+	switch {
+	case a == "" || b == "":
+		return 0.5
+	case a == b:
+		return 1
+	case strings.HasPrefix(a, b) || strings.HasPrefix(b, a):
+		return 0.8
+	case []rune(a)[0] == []rune(b)[0]:
+		return 0.6
+	default:
+		return 0
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
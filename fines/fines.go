@@ -0,0 +1,66 @@
+package fines
+
+import (
+	"strings"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/config"
+)
+
+const dateLayout = "2006-01-02"
+
+// Accrued returns the fine owed for a loan of a book in the given format that was due at
+// dueAt and returned, or is being checked, at end. Only days the library is open count,
+// the grace period is forgiven, and the result never exceeds the rule's cap.
+func Accrued(rules config.FineRules, format string, dueAt, end time.Time) int64 {
+	rule := rules.Rule(format)
+
+	days := OverdueDays(rules, dueAt, end) - rule.GraceDays
+	if days <= 0 {
+		return 0
+	}
+
+	amount := int64(days) * rule.DailyRate
+	if rule.MaxFine > 0 && amount > rule.MaxFine {
+		amount = rule.MaxFine
+	}
+
+	return amount
+}
+
+// OverdueDays counts the open days after the due date up to and including the day of end.
+func OverdueDays(rules config.FineRules, dueAt, end time.Time) int {
+	if !end.After(dueAt) {
+		return 0
+	}
+
+	closedDates := make(map[string]bool, len(rules.ClosedDates))
+	for _, d := range rules.ClosedDates {
+		closedDates[d] = true
+	}
+
+	closedWeekdays := make(map[time.Weekday]bool, len(rules.ClosedWeekdays))
+	for _, name := range rules.ClosedWeekdays {
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if strings.EqualFold(wd.String(), name) {
+				closedWeekdays[wd] = true
+			}
+		}
+	}
+
+	last := truncateToDay(end.In(time.Local))
+	days := 0
+
+	for day := truncateToDay(dueAt.In(time.Local)).AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if closedWeekdays[day.Weekday()] || closedDates[day.Format(dateLayout)] {
+			continue
+		}
+		days++
+	}
+
+	return days
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
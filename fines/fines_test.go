@@ -0,0 +1,98 @@
+package fines
+
+import (
+	"testing"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/config"
+)
+
+func TestAccrued(t *testing.T) {
+	rules := config.FineRules{
+		Default: config.FineRule{DailyRate: 500, GraceDays: 2, MaxFine: 5000},
+		Formats: map[string]config.FineRule{
+			"audiobook": {DailyRate: 1000},
+		},
+		ClosedWeekdays: []string{"Sunday"},
+	}
+
+	// A Monday.
+	dueAt := time.Date(2022, time.August, 29, 12, 0, 0, 0, time.Local)
+	day := func(month time.Month, d, hour int) time.Time {
+		return time.Date(2022, month, d, hour, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name        string
+		format      string
+		closedDates []string
+		end         time.Time
+		wantDays    int
+		wantFine    int64
+	}{
+		{
+			name: "returned before the due date",
+			end:  day(time.August, 28, 10),
+		},
+		{
+			name: "returned on the due date after the due time",
+			end:  day(time.August, 29, 18),
+		},
+		{
+			name:     "within the grace period",
+			end:      day(time.August, 30, 10),
+			wantDays: 1,
+		},
+		{
+			name:     "on the last day of the grace period",
+			end:      day(time.August, 31, 10),
+			wantDays: 2,
+		},
+		{
+			name:     "after the grace period",
+			end:      day(time.September, 2, 10),
+			wantDays: 4,
+			wantFine: 1000,
+		},
+		{
+			name:        "closed date not counted",
+			closedDates: []string{"2022-09-01"},
+			end:         day(time.September, 2, 10),
+			wantDays:    3,
+			wantFine:    500,
+		},
+		{
+			name:     "closed weekday not counted",
+			end:      day(time.September, 5, 10),
+			wantDays: 6,
+			wantFine: 2000,
+		},
+		{
+			name:     "capped",
+			end:      day(time.September, 30, 10),
+			wantDays: 28,
+			wantFine: 5000,
+		},
+		{
+			name:     "rule of the format without grace or cap",
+			format:   "audiobook",
+			end:      day(time.September, 30, 10),
+			wantDays: 28,
+			wantFine: 28000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := rules
+			rules.ClosedDates = tt.closedDates
+
+			if days := OverdueDays(rules, dueAt, tt.end); days != tt.wantDays {
+				t.Errorf("OverdueDays = %d, want %d", days, tt.wantDays)
+			}
+			if fine := Accrued(rules, tt.format, dueAt, tt.end); fine != tt.wantFine {
+				t.Errorf("Accrued = %d, want %d", fine, tt.wantFine)
+			}
+		})
+	}
+}
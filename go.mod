@@ -3,7 +3,10 @@ module github.com/saidakhmatov/catalog_of_books
 go 1.18
 
 require (
+	github.com/gin-contrib/sse v0.1.0
+	github.com/go-playground/validator/v10 v10.10.0
 	github.com/google/uuid v1.3.0
+	github.com/graph-gophers/graphql-go v1.5.0
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/joho/godotenv v1.4.0
 	github.com/lib/pq v1.10.6
@@ -11,13 +14,14 @@ require (
 	github.com/swaggo/files v0.0.0-20220728132757-551d4a08d97a
 	github.com/swaggo/gin-swagger v1.5.1
 	github.com/swaggo/swag v1.8.4
+	github.com/vektah/gqlparser/v2 v2.5.8
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
-	github.com/gin-contrib/sse v0.1.0 // indirect
+	github.com/agnivade/levenshtein v1.1.1 // indirect
 	github.com/go-playground/locales v0.14.0 // indirect
 	github.com/go-playground/universal-translator v0.18.0 // indirect
-	github.com/go-playground/validator/v10 v10.10.0 // indirect
 	github.com/goccy/go-json v0.9.7 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/leodido/go-urn v1.2.1 // indirect
@@ -43,7 +47,6 @@ require (
 	github.com/mailru/easyjson v0.7.6 // indirect
 	golang.org/x/net v0.0.0-20220425223048-2871e0cb64e4 // indirect
 	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
-	golang.org/x/text v0.3.7 // indirect
+	golang.org/x/text v0.3.7
 	golang.org/x/tools v0.1.10 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
@@ -0,0 +1,320 @@
+package graph
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// loads runs each load of a set of objects once, however many of its objects ask for
+// it. The objects of a list are resolved concurrently, so the first to ask loads the
+// relation for all of them and the others wait for it.
+type loads struct {
+	mu    sync.Mutex
+	byKey map[string]*load
+}
+
+type load struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+func (l *loads) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	l.mu.Lock()
+	if l.byKey == nil {
+		l.byKey = make(map[string]*load)
+	}
+	ld, ok := l.byKey[key]
+	if !ok {
+		ld = &load{}
+		l.byKey[key] = ld
+	}
+	l.mu.Unlock()
+
+	ld.once.Do(func() { ld.value, ld.err = fn() })
+	return ld.value, ld.err
+}
+
+// bookSet is the books of one level of a response, whose relations are loaded together.
+type bookSet struct {
+	r     *resolver
+	books []models.Book
+	loads loads
+}
+
+type bookResolver struct {
+	book models.Book
+	set  *bookSet
+}
+
+func (r *resolver) books(books []models.Book) []*bookResolver {
+	set := &bookSet{r: r, books: books}
+
+	resp := make([]*bookResolver, len(books))
+	for i := range books {
+		resp[i] = &bookResolver{book: books[i], set: set}
+	}
+	return resp
+}
+
+func (b *bookResolver) ID() graphql.ID           { return graphql.ID(b.book.ID) }
+func (b *bookResolver) Slug() string             { return b.book.Slug }
+func (b *bookResolver) BookName() string         { return b.book.BookName }
+func (b *bookResolver) AuthorID() graphql.ID     { return graphql.ID(b.book.AuthorID) }
+func (b *bookResolver) CategoryID() graphql.ID   { return graphql.ID(b.book.CategoryID) }
+func (b *bookResolver) PublicationYear() *int32  { return int32Ptr(b.book.PublicationYear) }
+func (b *bookResolver) Language() string         { return b.book.Language }
+func (b *bookResolver) PageCount() *int32        { return int32Ptr(b.book.PageCount) }
+func (b *bookResolver) Description() string      { return b.book.Description }
+func (b *bookResolver) Edition() string          { return b.book.Edition }
+func (b *bookResolver) Format() string           { return b.book.Format }
+func (b *bookResolver) SeriesPosition() *float64 { return b.book.SeriesPosition }
+func (b *bookResolver) AverageRating() float64   { return b.book.AverageRating }
+func (b *bookResolver) RatingCount() int32       { return int32(b.book.RatingCount) }
+func (b *bookResolver) CreatedAt() string        { return timestamp(b.book.CreatedAt) }
+func (b *bookResolver) UpdatedAt() string        { return timestamp(b.book.UpdatedAt) }
+
+func (b *bookResolver) SeriesID() *graphql.ID {
+	if b.book.SeriesID == nil {
+		return nil
+	}
+	id := graphql.ID(*b.book.SeriesID)
+	return &id
+}
+
+// Author loads the authors of all the books of the set in one read.
+func (b *bookResolver) Author() (*authorResolver, error) {
+	value, err := b.set.loads.do("author", func() (interface{}, error) {
+		var ids []string
+		for _, book := range b.set.books {
+			ids = append(ids, book.AuthorID)
+		}
+
+		authors, err := b.set.r.strg.AuthorRepo().GetAuthorsByIDs(distinct(ids))
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[string]*authorResolver)
+		for _, author := range b.set.r.authors(authors) {
+			byID[author.author.ID] = author
+		}
+		return byID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]*authorResolver)[b.book.AuthorID], nil
+}
+
+// Category loads the categories of all the books of the set in one read.
+func (b *bookResolver) Category() (*categoryResolver, error) {
+	value, err := b.set.loads.do("category", func() (interface{}, error) {
+		var ids []string
+		for _, book := range b.set.books {
+			ids = append(ids, book.CategoryID)
+		}
+
+		categories, err := b.set.r.strg.BookCategoryRepo().GetBookCategoriesByIDs(distinct(ids))
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[string]*categoryResolver)
+		for _, category := range b.set.r.categories(categories) {
+			byID[category.category.ID] = category
+		}
+		return byID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]*categoryResolver)[b.book.CategoryID], nil
+}
+
+// Siblings loads the other books of the series of all the books of the set in one
+// read. Books outside a series have no siblings.
+func (b *bookResolver) Siblings(args limitArgs) ([]*bookResolver, error) {
+	limit, err := listLimit(args.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := b.set.loads.do(fmt.Sprintf("siblings:%d", limit), func() (interface{}, error) {
+		var seriesIDs []string
+		for _, book := range b.set.books {
+			if book.SeriesID != nil {
+				seriesIDs = append(seriesIDs, *book.SeriesID)
+			}
+		}
+
+		bySeries := make(map[string][]*bookResolver)
+		if len(seriesIDs) == 0 {
+			return bySeries, nil
+		}
+
+		// One more than the limit, as each book is among the books of its series.
+		books, err := b.set.r.strg.BookRepo().GetBooksBySeriesIDs(distinct(seriesIDs), limit+1, bookFields...)
+		if err != nil {
+			return nil, err
+		}
+		for _, book := range b.set.r.books(books) {
+			bySeries[*book.book.SeriesID] = append(bySeries[*book.book.SeriesID], book)
+		}
+		return bySeries, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	siblings := []*bookResolver{}
+	if b.book.SeriesID != nil {
+		for _, sibling := range value.(map[string][]*bookResolver)[*b.book.SeriesID] {
+			if sibling.book.ID != b.book.ID && len(siblings) < limit {
+				siblings = append(siblings, sibling)
+			}
+		}
+	}
+	return siblings, nil
+}
+
+// authorSet is the authors of one level of a response, whose books are loaded together.
+type authorSet struct {
+	r       *resolver
+	authors []models.Author
+	loads   loads
+}
+
+type authorResolver struct {
+	author models.Author
+	set    *authorSet
+}
+
+func (r *resolver) authors(authors []models.Author) []*authorResolver {
+	set := &authorSet{r: r, authors: authors}
+
+	resp := make([]*authorResolver, len(authors))
+	for i := range authors {
+		resp[i] = &authorResolver{author: authors[i], set: set}
+	}
+	return resp
+}
+
+func (a *authorResolver) ID() graphql.ID      { return graphql.ID(a.author.ID) }
+func (a *authorResolver) Slug() string        { return a.author.Slug }
+func (a *authorResolver) Firstname() string   { return a.author.Firstname }
+func (a *authorResolver) Lastname() string    { return a.author.Lastname }
+func (a *authorResolver) DisplayName() string { return a.author.DisplayName }
+func (a *authorResolver) SortName() string    { return a.author.SortName }
+func (a *authorResolver) BirthDate() string   { return a.author.BirthDate }
+func (a *authorResolver) DeathDate() string   { return a.author.DeathDate }
+func (a *authorResolver) Nationality() string { return a.author.Nationality }
+func (a *authorResolver) Biography() string   { return a.author.Biography }
+func (a *authorResolver) Aliases() []string   { return a.author.Aliases }
+func (a *authorResolver) CreatedAt() string   { return timestamp(a.author.CreatedAt) }
+func (a *authorResolver) UpdatedAt() string   { return timestamp(a.author.UpdatedAt) }
+
+// Books loads the books of all the authors of the set in one read.
+func (a *authorResolver) Books(args limitArgs) ([]*bookResolver, error) {
+	limit, err := listLimit(args.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := a.set.loads.do(fmt.Sprintf("books:%d", limit), func() (interface{}, error) {
+		var ids []string
+		for _, author := range a.set.authors {
+			ids = append(ids, author.ID)
+		}
+
+		books, err := a.set.r.strg.BookRepo().GetBooksByAuthorIDs(distinct(ids), limit, bookFields...)
+		if err != nil {
+			return nil, err
+		}
+
+		byAuthor := make(map[string][]*bookResolver)
+		for _, book := range a.set.r.books(books) {
+			byAuthor[book.book.AuthorID] = append(byAuthor[book.book.AuthorID], book)
+		}
+		return byAuthor, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string][]*bookResolver)[a.author.ID], nil
+}
+
+// categorySet is the categories of one level of a response, whose books are loaded
+// together.
+type categorySet struct {
+	r          *resolver
+	categories []models.BookCategory
+	loads      loads
+}
+
+type categoryResolver struct {
+	category models.BookCategory
+	set      *categorySet
+}
+
+func (r *resolver) categories(categories []models.BookCategory) []*categoryResolver {
+	set := &categorySet{r: r, categories: categories}
+
+	resp := make([]*categoryResolver, len(categories))
+	for i := range categories {
+		resp[i] = &categoryResolver{category: categories[i], set: set}
+	}
+	return resp
+}
+
+func (c *categoryResolver) ID() graphql.ID       { return graphql.ID(c.category.ID) }
+func (c *categoryResolver) Slug() string         { return c.category.Slug }
+func (c *categoryResolver) CategoryName() string { return c.category.CategoryName }
+func (c *categoryResolver) CreatedAt() string    { return timestamp(c.category.CreatedAt) }
+func (c *categoryResolver) UpdatedAt() string    { return timestamp(c.category.UpdatedAt) }
+
+// Books loads the books of all the categories of the set in one read.
+func (c *categoryResolver) Books(args limitArgs) ([]*bookResolver, error) {
+	limit, err := listLimit(args.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.set.loads.do(fmt.Sprintf("books:%d", limit), func() (interface{}, error) {
+		var ids []string
+		for _, category := range c.set.categories {
+			ids = append(ids, category.ID)
+		}
+
+		books, err := c.set.r.strg.BookRepo().GetBooksByCategoryIDs(distinct(ids), limit, bookFields...)
+		if err != nil {
+			return nil, err
+		}
+
+		byCategory := make(map[string][]*bookResolver)
+		for _, book := range c.set.r.books(books) {
+			byCategory[book.book.CategoryID] = append(byCategory[book.book.CategoryID], book)
+		}
+		return byCategory, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string][]*bookResolver)[c.category.ID], nil
+}
+
+func int32Ptr(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
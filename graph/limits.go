@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// DefaultListSize is the expected length of a list field without a limit argument.
+const DefaultListSize = 10
+
+// MaxIntrospectionLists caps how deep introspection lists are nested. Introspection is
+// left out of the limits as it never reaches the storage, but each nested list of it
+// multiplies the size of the response. The introspection query of GraphiQL and of code
+// generators nests three: the arguments of the fields of the types.
+const MaxIntrospectionLists = 3
+
+// maxCost caps the counts of a measure, so that they cannot overflow.
+const maxCost = math.MaxInt32
+
+// measure is the size of a selection set.
+type measure struct {
+	depth         int
+	complexity    int
+	introspection int // introspection lists nested
+}
+
+// check validates the operation of params and measures it against the limits of the
+// schema, with its variables applied.
+func (s *Schema) check(params Params) []*gqlerrors.QueryError {
+	doc, errs := gqlparser.LoadQuery(s.ast, params.Query)
+	if len(errs) > 0 {
+		resp := make([]*gqlerrors.QueryError, len(errs))
+		for i, err := range errs {
+			resp[i] = queryError(err)
+		}
+		return resp
+	}
+
+	op := doc.Operations.ForName(params.OperationName)
+	if op == nil {
+		if len(params.OperationName) == 0 {
+			return []*gqlerrors.QueryError{gqlerrors.Errorf("the operation to run must be named")}
+		}
+		return []*gqlerrors.QueryError{gqlerrors.Errorf("no operation named %q", params.OperationName)}
+	}
+
+	vars, err := validator.VariableValues(s.ast, op, params.Variables)
+	if err != nil {
+		var gqlErr *gqlerror.Error
+		if errors.As(err, &gqlErr) {
+			return []*gqlerrors.QueryError{queryError(gqlErr)}
+		}
+		return []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}
+	}
+
+	m := measurer{vars: vars, fragments: make(map[string]measure)}
+	size := m.selections(op.SelectionSet)
+
+	switch {
+	case s.limits.MaxDepth > 0 && size.depth > s.limits.MaxDepth:
+		return []*gqlerrors.QueryError{gqlerrors.Errorf("query depth %d exceeds the limit of %d", size.depth, s.limits.MaxDepth)}
+	case s.limits.MaxComplexity > 0 && size.complexity > s.limits.MaxComplexity:
+		return []*gqlerrors.QueryError{gqlerrors.Errorf("query complexity %d exceeds the limit of %d", size.complexity, s.limits.MaxComplexity)}
+	case size.introspection > MaxIntrospectionLists:
+		return []*gqlerrors.QueryError{gqlerrors.Errorf("introspection lists nested %d deep exceed the limit of %d", size.introspection, MaxIntrospectionLists)}
+	}
+	return nil
+}
+
+func queryError(err *gqlerror.Error) *gqlerrors.QueryError {
+	resp := &gqlerrors.QueryError{Message: err.Message}
+	if len(err.Path) > 0 {
+		resp.Message = err.Path.String() + " " + err.Message
+	}
+	for _, loc := range err.Locations {
+		resp.Locations = append(resp.Locations, gqlerrors.Location{Line: loc.Line, Column: loc.Column})
+	}
+	return resp
+}
+
+// measurer measures the selections of a validated operation. A fragment measures the
+// same wherever it is spread, so each is measured once, however many times it is
+// spread.
+type measurer struct {
+	vars      map[string]interface{}
+	fragments map[string]measure
+}
+
+func (m *measurer) selections(selections ast.SelectionSet) measure {
+	var size measure
+
+	for _, selection := range selections {
+		var child measure
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if m.skipped(selection.Directives) {
+				continue
+			}
+			child = m.field(selection)
+		case *ast.InlineFragment:
+			if m.skipped(selection.Directives) {
+				continue
+			}
+			child = m.selections(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if m.skipped(selection.Directives) {
+				continue
+			}
+			var ok bool
+			if child, ok = m.fragments[selection.Name]; !ok {
+				child = m.selections(selection.Definition.SelectionSet)
+				m.fragments[selection.Name] = child
+			}
+		}
+
+		size.depth = max(size.depth, child.depth)
+		size.complexity = capped(size.complexity + child.complexity)
+		size.introspection = max(size.introspection, child.introspection)
+	}
+
+	return size
+}
+
+// field measures a field: one, plus its selections once per object it is expected to
+// have.
+func (m *measurer) field(field *ast.Field) measure {
+	if field.Name == "__typename" {
+		return measure{depth: 1, complexity: 1}
+	}
+
+	child := m.selections(field.SelectionSet)
+
+	if strings.HasPrefix(field.Name, "__") || strings.HasPrefix(field.ObjectDefinition.Name, "__") {
+		if field.Definition.Type.Elem != nil {
+			child.introspection++
+		}
+		return measure{complexity: capped(1 + child.complexity), introspection: child.introspection}
+	}
+
+	size := 1
+	if field.Definition.Type.Elem != nil {
+		size = m.listSize(field)
+	}
+
+	return measure{
+		depth:         child.depth + 1,
+		complexity:    capped(1 + capped(size)*child.complexity),
+		introspection: child.introspection,
+	}
+}
+
+// listSize is the number of objects a list is expected to have: the number of ids asked
+// for, or its limit.
+func (m *measurer) listSize(field *ast.Field) int {
+	if ids, given := m.argument(field, "ids"); given && ids != nil {
+		if list, ok := ids.([]interface{}); ok {
+			return len(list)
+		}
+		return 1
+	}
+
+	limit, given := m.argument(field, "limit")
+	if !given || limit == nil {
+		return DefaultListSize
+	}
+
+	switch limit := limit.(type) {
+	case int64:
+		return int(math.Max(0, math.Min(float64(limit), maxCost)))
+	case float64:
+		return int(math.Max(0, math.Min(limit, maxCost)))
+	}
+	return DefaultListSize
+}
+
+// argument returns the value of an argument of a field, or its default if it is not
+// given.
+func (m *measurer) argument(field *ast.Field, name string) (interface{}, bool) {
+	definition := field.Definition.Arguments.ForName(name)
+	if definition == nil {
+		return nil, false
+	}
+
+	if arg := field.Arguments.ForName(name); arg != nil {
+		_, isVariableGiven := m.vars[arg.Value.Raw]
+		if arg.Value.Kind != ast.Variable || isVariableGiven {
+			value, err := arg.Value.Value(m.vars)
+			return value, err == nil
+		}
+	}
+
+	if definition.DefaultValue == nil {
+		return nil, false
+	}
+	value, err := definition.DefaultValue.Value(nil)
+	return value, err == nil
+}
+
+// skipped evaluates the @skip and @include directives.
+func (m *measurer) skipped(directives ast.DirectiveList) bool {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+
+		arg := directive.Arguments.ForName("if")
+		if arg == nil {
+			continue
+		}
+		condition, err := arg.Value.Value(m.vars)
+		if err != nil {
+			continue
+		}
+		if is, ok := condition.(bool); ok && is == (directive.Name == "skip") {
+			return true
+		}
+	}
+	return false
+}
+
+func capped(n int) int {
+	if n > maxCost {
+		return maxCost
+	}
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
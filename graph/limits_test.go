@@ -0,0 +1,313 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// introspectionQuery is the introspection query of GraphiQL.
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) {
+    name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType {
+    kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } }
+}`
+
+// expandingQuery returns a query of levels levels of fragments, each spreading the
+// fragments of the next level, width of them, and selecting fields on the last.
+func expandingQuery(levels, width int, distinct bool) string {
+	name := func(level, i int) string {
+		if distinct {
+			return fmt.Sprintf("L%dF%d", level, i)
+		}
+		return fmt.Sprintf("L%d", level)
+	}
+	spreads := func(level int) string {
+		var b strings.Builder
+		for i := 0; i < width; i++ {
+			b.WriteString(" ..." + name(level, i))
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString(`{ book(id: "b1") {` + spreads(0) + ` } }`)
+	for level := 0; level < levels; level++ {
+		count := 1
+		if distinct {
+			count = width
+		}
+		for i := 0; i < count; i++ {
+			body := spreads(level + 1)
+			if level == levels-1 {
+				body = " id book_name"
+			}
+			fmt.Fprintf(&b, " fragment %s on Book {%s }", name(level, i), body)
+		}
+	}
+	return b.String()
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		limits    Limits
+		wantErr   string // part of the first error, none if empty
+	}{
+		{
+			name:  "valid query",
+			query: `{ book(id: "b1") { id author { display_name } } }`,
+		},
+
+		// validation
+		{
+			name:    "parse error",
+			query:   `{ book(id: "b1") { id }`,
+			wantErr: "Expected Name, found <EOF>",
+		},
+		{
+			name:    "unknown field",
+			query:   `{ book(id: "b1") { isbn } }`,
+			wantErr: `Cannot query field "isbn" on type "Book"`,
+		},
+		{
+			name:    "fragment spreading itself",
+			query:   `{ book(id: "b1") { ...A } } fragment A on Book { id ...A }`,
+			wantErr: `Cannot spread fragment "A" within itself`,
+		},
+		{
+			name:    "fragments spreading each other",
+			query:   `{ book(id: "b1") { ...A } } fragment A on Book { siblings { ...B } } fragment B on Book { id ...A }`,
+			wantErr: "within itself via",
+		},
+		{
+			name:    "same key with different arguments",
+			query:   `{ books(limit: 1000) { id } books(limit: 1) { id } }`,
+			limits:  Limits{MaxComplexity: 50},
+			wantErr: "differing arguments",
+		},
+		{
+			name:    "same key with different arguments in fragments",
+			query:   `{ ...A ...B } fragment A on Query { books(limit: 1) { id } } fragment B on Query { books { id } }`,
+			wantErr: "differing arguments",
+		},
+		{
+			name:      "same key with a literal and a variable argument",
+			query:     `query($n: Int) { books(limit: 1) { id } books(limit: $n) { book_name } }`,
+			variables: map[string]interface{}{"n": 1.0},
+			limits:    Limits{MaxComplexity: 3},
+			wantErr:   "differing arguments",
+		},
+		{
+			name:  "same key with the same literal arguments",
+			query: `{ books(limit: 1) { id } books(limit: 1) { book_name } }`,
+		},
+
+		// operations and variables
+		{
+			name:    "operation to run not named",
+			query:   `query A { books { id } } query B { authors { id } }`,
+			wantErr: "the operation to run must be named",
+		},
+		{
+			name:      "unknown operation",
+			query:     `query A { books { id } }`,
+			operation: "B",
+			wantErr:   `no operation named "B"`,
+		},
+		{
+			name:      "named operation",
+			query:     `query A { books { id } } query B { authors { id } }`,
+			operation: "B",
+		},
+		{
+			name:    "required variable",
+			query:   `query($id: ID!) { book(id: $id) { id } }`,
+			wantErr: "variable.id must be defined",
+		},
+		{
+			name:      "variable of the wrong type",
+			query:     `query($n: Int) { books(limit: $n) { id } }`,
+			variables: map[string]interface{}{"n": "ten"},
+			wantErr:   "variable.n cannot use string as Int",
+		},
+
+		// depth
+		{
+			name:   "within the depth",
+			query:  `{ book(id: "b1") { siblings { author { id } } } }`,
+			limits: Limits{MaxDepth: 4},
+		},
+		{
+			name:    "too deep",
+			query:   `{ book(id: "b1") { siblings { siblings { author { id } } } } }`,
+			limits:  Limits{MaxDepth: 4},
+			wantErr: "query depth 5 exceeds the limit of 4",
+		},
+		{
+			name:    "too deep through a fragment spread again deeper",
+			query:   `{ book(id: "b1") { ...F siblings { siblings { ...F } } } } fragment F on Book { author { books { id } } }`,
+			limits:  Limits{MaxDepth: 5},
+			wantErr: "query depth 6 exceeds the limit of 5",
+		},
+
+		// complexity
+		{
+			name:   "within the complexity",
+			query:  `{ books(limit: 2) { siblings(limit: 1) { id } } }`,
+			limits: Limits{MaxComplexity: 5},
+		},
+		{
+			name:    "too complex",
+			query:   `{ books(limit: 50) { siblings(limit: 5) { id } } }`,
+			limits:  Limits{MaxComplexity: 300},
+			wantErr: "query complexity 301 exceeds the limit of 300",
+		},
+		{
+			name:    "list size defaults to the argument default",
+			query:   `{ books { id } }`,
+			limits:  Limits{MaxComplexity: 10},
+			wantErr: "query complexity 11 exceeds the limit of 10",
+		},
+		{
+			name:   "list size of the ids asked for",
+			query:  `{ books(ids: ["b1", "b2"], limit: 100) { id } }`,
+			limits: Limits{MaxComplexity: 3},
+		},
+		{
+			name:      "list size from a variable",
+			query:     `query($n: Int) { books(limit: $n) { id } }`,
+			variables: map[string]interface{}{"n": 100.0},
+			limits:    Limits{MaxComplexity: 100},
+			wantErr:   "query complexity 101 exceeds the limit of 100",
+		},
+		{
+			name:    "list size of a variable not given",
+			query:   `query($n: Int) { books(limit: $n) { id } }`,
+			limits:  Limits{MaxComplexity: 10},
+			wantErr: "query complexity 11 exceeds the limit of 10",
+		},
+		{
+			name:    "aliases count separately",
+			query:   `{ a: book(id: "b1") { id } b: book(id: "b1") { id } c: book(id: "b1") { id } }`,
+			limits:  Limits{MaxComplexity: 5},
+			wantErr: "query complexity 6 exceeds the limit of 5",
+		},
+		{
+			name:    "mutations are measured",
+			query:   `mutation { create_book_category(input: {category_name: "x"}) { books(limit: 100) { id } } }`,
+			limits:  Limits{MaxComplexity: 100},
+			wantErr: "query complexity 102 exceeds the limit of 100",
+		},
+
+		// @skip and @include
+		{
+			name:   "skipped fields are not measured",
+			query:  `{ books(limit: 50) @skip(if: true) { id } book(id: "b1") { id } }`,
+			limits: Limits{MaxComplexity: 2},
+		},
+		{
+			name:      "fields not included are not measured",
+			query:     `query($deep: Boolean!) { book(id: "b1") { id ... on Book @include(if: $deep) { siblings { siblings { id } } } } }`,
+			variables: map[string]interface{}{"deep": false},
+			limits:    Limits{MaxDepth: 2},
+		},
+		{
+			name:      "included fields are measured",
+			query:     `query($deep: Boolean!) { book(id: "b1") { id ... on Book @include(if: $deep) { siblings { siblings { id } } } } }`,
+			variables: map[string]interface{}{"deep": true},
+			limits:    Limits{MaxDepth: 2},
+			wantErr:   "query depth 4 exceeds the limit of 2",
+		},
+
+		// fragment expansion
+		{
+			name:   "fragment spread twice in a selection set",
+			query:  `{ book(id: "b1") { ...F ...F } } fragment F on Book { id }`,
+			limits: Limits{MaxComplexity: 3},
+		},
+		{
+			name:    "repeated spreads of nested fragments",
+			query:   expandingQuery(6, 20, false),
+			limits:  Limits{MaxDepth: 8, MaxComplexity: 2000},
+			wantErr: "exceeds the limit of 2000",
+		},
+		{
+			name:    "distinct fragments expanding exponentially",
+			query:   expandingQuery(40, 2, true),
+			limits:  Limits{MaxDepth: 8, MaxComplexity: 2000},
+			wantErr: "exceeds the limit of 2000",
+		},
+		{
+			name:   "distinct fragments within the limits",
+			query:  expandingQuery(4, 2, true),
+			limits: Limits{MaxDepth: 8, MaxComplexity: 2000},
+		},
+
+		// introspection
+		{
+			name:   "introspection query of GraphiQL",
+			query:  introspectionQuery,
+			limits: Limits{MaxDepth: 8, MaxComplexity: 2000},
+		},
+		{
+			name:   "typename",
+			query:  `{ book(id: "b1") { __typename } }`,
+			limits: Limits{MaxDepth: 2, MaxComplexity: 2},
+		},
+		{
+			name:    "introspection lists nested too deep",
+			query:   `{ __schema { types { fields { type { fields { type { fields { name } } } } } } } }`,
+			wantErr: "introspection lists nested 4 deep exceed the limit of 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewSchema(nil, nil, tt.limits)
+			if err != nil {
+				t.Fatalf("NewSchema: %v", err)
+			}
+
+			start := time.Now()
+			errs := schema.check(Params{Query: tt.query, OperationName: tt.operation, Variables: tt.variables})
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("check took %v", elapsed)
+			}
+
+			switch {
+			case len(tt.wantErr) == 0 && len(errs) > 0:
+				t.Fatalf("check = %v, want no errors", errs)
+			case len(tt.wantErr) > 0 && len(errs) == 0:
+				t.Fatalf("check passed, want error %q", tt.wantErr)
+			case len(tt.wantErr) > 0 && !strings.Contains(errs[0].Message, tt.wantErr):
+				t.Fatalf("check = %v, want error containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// bookFields are the book fields the schema exposes. Copies and series neighbours are
+// left out as they are read per book.
+var bookFields = []string{
+	"id", "slug", "book_name", "author_id", "category_id", "publication_year", "language",
+	"page_count", "description", "edition", "format", "series_id", "series_position",
+	"average_rating", "rating_count", "created_at", "updated_at",
+}
+
+// maxListLimit caps the limit of every list.
+const maxListLimit = helper.MaxBatchIDs
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+type listArgs struct {
+	IDs    *[]graphql.ID
+	Search *string
+	Limit  int32
+	Offset int32
+}
+
+type bookListArgs struct {
+	IDs      *[]graphql.ID
+	Search   *string
+	Limit    int32
+	Offset   int32
+	Language *string
+	Format   *string
+	YearFrom *int32
+	YearTo   *int32
+	Sort     *string
+}
+
+type limitArgs struct {
+	Limit int32
+}
+
+func (r *resolver) Book(args idArgs) (*bookResolver, error) {
+	book, err := r.strg.BookRepo().GetBook(string(args.ID), bookFields...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.books([]models.Book{book})[0], nil
+}
+
+func (r *resolver) Books(args bookListArgs) ([]*bookResolver, error) {
+	ids, err := batchIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if ids != nil {
+		books, err := r.strg.BookRepo().GetBooksByIDs(ids, bookFields...)
+		return r.books(books), err
+	}
+
+	page, err := pageParams(listArgs{Search: args.Search, Limit: args.Limit, Offset: args.Offset})
+	if err != nil {
+		return nil, err
+	}
+	page.Fields = bookFields
+
+	qP := models.BookQueryParamModel{ApplicationQueryParamModel: page}
+	qP.Language = stringValue(args.Language)
+	qP.Format = stringValue(args.Format)
+	qP.YearFrom = intValue(args.YearFrom)
+	qP.YearTo = intValue(args.YearTo)
+	qP.Sort = stringValue(args.Sort)
+
+	books, err := r.strg.BookRepo().GetAllBooks(qP)
+	return r.books(books), err
+}
+
+func (r *resolver) Author(args idArgs) (*authorResolver, error) {
+	author, err := r.strg.AuthorRepo().GetAuthor(string(args.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.authors([]models.Author{author})[0], nil
+}
+
+func (r *resolver) Authors(args listArgs) ([]*authorResolver, error) {
+	ids, err := batchIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if ids != nil {
+		authors, err := r.strg.AuthorRepo().GetAuthorsByIDs(ids)
+		return r.authors(authors), err
+	}
+
+	page, err := pageParams(args)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := r.strg.AuthorRepo().GetAllAuthors(page)
+	return r.authors(authors), err
+}
+
+func (r *resolver) BookCategory(args idArgs) (*categoryResolver, error) {
+	category, err := r.strg.BookCategoryRepo().GetBookCategory(string(args.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.categories([]models.BookCategory{category})[0], nil
+}
+
+func (r *resolver) BookCategories(args listArgs) ([]*categoryResolver, error) {
+	ids, err := batchIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if ids != nil {
+		categories, err := r.strg.BookCategoryRepo().GetBookCategoriesByIDs(ids)
+		return r.categories(categories), err
+	}
+
+	page, err := pageParams(args)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := r.strg.BookCategoryRepo().GetAllBookCategories(page)
+	return r.categories(categories), err
+}
+
+type createBookInput struct {
+	CategoryID      graphql.ID `json:"category_id"`
+	AuthorID        graphql.ID `json:"author_id"`
+	BookName        string     `json:"book_name"`
+	PublicationYear *int32     `json:"publication_year"`
+	Language        *string    `json:"language"`
+	PageCount       *int32     `json:"page_count"`
+	Description     *string    `json:"description"`
+	Edition         *string    `json:"edition"`
+	Format          *string    `json:"format"`
+}
+
+type updateBookInput struct {
+	CategoryID      *graphql.ID `json:"category_id"`
+	AuthorID        *graphql.ID `json:"author_id"`
+	BookName        *string     `json:"book_name"`
+	PublicationYear *int32      `json:"publication_year"`
+	Language        *string     `json:"language"`
+	PageCount       *int32      `json:"page_count"`
+	Description     *string     `json:"description"`
+	Edition         *string     `json:"edition"`
+	Format          *string     `json:"format"`
+}
+
+type createAuthorInput struct {
+	Firstname   string    `json:"firstname"`
+	Lastname    string    `json:"lastname"`
+	BirthDate   *string   `json:"birth_date"`
+	DeathDate   *string   `json:"death_date"`
+	Nationality *string   `json:"nationality"`
+	Biography   *string   `json:"biography"`
+	Aliases     *[]string `json:"aliases"`
+}
+
+type updateAuthorInput struct {
+	Firstname   *string   `json:"firstname"`
+	Lastname    *string   `json:"lastname"`
+	BirthDate   *string   `json:"birth_date"`
+	DeathDate   *string   `json:"death_date"`
+	Nationality *string   `json:"nationality"`
+	Biography   *string   `json:"biography"`
+	Aliases     *[]string `json:"aliases"`
+}
+
+type categoryInput struct {
+	CategoryName string `json:"category_name"`
+}
+
+func (r *resolver) CreateBook(ctx context.Context, args struct{ Input createBookInput }) (*bookResolver, error) {
+	var input models.CreateBook
+	if err := decodeInput(args.Input, &input); err != nil {
+		return nil, err
+	}
+
+	dt := time.Now()
+	book := models.Book{
+		ID:              r.ids.NewID(),
+		CategoryID:      input.CategoryID,
+		AuthorID:        input.AuthorID,
+		BookName:        input.BookName,
+		PublicationYear: input.PublicationYear,
+		Language:        input.Language,
+		PageCount:       input.PageCount,
+		Description:     input.Description,
+		Edition:         input.Edition,
+		Format:          input.Format,
+		CreatedAt:       dt,
+		UpdatedAt:       dt,
+	}
+
+	res, err := r.strg.BookRepo().CreateBook(book, actorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return r.books([]models.Book{res})[0], nil
+}
+
+func (r *resolver) UpdateBook(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateBookInput
+}) (*bookResolver, error) {
+	id, err := entityID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var input models.UpdateBook
+	if err := decodeInput(args.Input, &input); err != nil {
+		return nil, err
+	}
+
+	res, err := r.strg.BookRepo().UpdateBook(input, id, actorFrom(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.books([]models.Book{res})[0], nil
+}
+
+func (r *resolver) DeleteBook(ctx context.Context, args idArgs) (bool, error) {
+	id, err := entityID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := r.strg.BookRepo().DeleteBook(id, actorFrom(ctx))
+	return rows > 0, err
+}
+
+func (r *resolver) CreateAuthor(ctx context.Context, args struct{ Input createAuthorInput }) (*authorResolver, error) {
+	var input models.CreateAuthor
+	if err := decodeInput(args.Input, &input); err != nil {
+		return nil, err
+	}
+
+	dt := time.Now()
+	author := models.Author{
+		ID:          r.ids.NewID(),
+		Firstname:   input.Firstname,
+		Lastname:    input.Lastname,
+		BirthDate:   input.BirthDate,
+		DeathDate:   input.DeathDate,
+		Nationality: input.Nationality,
+		Biography:   input.Biography,
+		Aliases:     input.Aliases,
+		CreatedAt:   dt,
+		UpdatedAt:   dt,
+	}
+
+	res, err := r.strg.AuthorRepo().CreateAuthor(author, actorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return r.authors([]models.Author{res})[0], nil
+}
+
+func (r *resolver) UpdateAuthor(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateAuthorInput
+}) (*authorResolver, error) {
+	id, err := entityID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var input models.UpdateAuthor
+	if err := decodeInput(args.Input, &input); err != nil {
+		return nil, err
+	}
+
+	res, err := r.strg.AuthorRepo().UpdateAuthor(input, id, actorFrom(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.authors([]models.Author{res})[0], nil
+}
+
+func (r *resolver) DeleteAuthor(ctx context.Context, args idArgs) (bool, error) {
+	id, err := entityID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := r.strg.AuthorRepo().DeleteAuthor(id, actorFrom(ctx))
+	return rows > 0, err
+}
+
+func (r *resolver) CreateBookCategory(ctx context.Context, args struct{ Input categoryInput }) (*categoryResolver, error) {
+	var input models.CreateBookCategory
+	if err := decodeInput(args.Input, &input); err != nil {
+		return nil, err
+	}
+
+	dt := time.Now()
+	category := models.BookCategory{
+		ID:           r.ids.NewID(),
+		CategoryName: input.CategoryName,
+		CreatedAt:    dt,
+		UpdatedAt:    dt,
+	}
+
+	res, err := r.strg.BookCategoryRepo().CreateBookCategory(category, actorFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return r.categories([]models.BookCategory{res})[0], nil
+}
+
+func (r *resolver) UpdateBookCategory(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input categoryInput
+}) (*categoryResolver, error) {
+	id, err := entityID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var input models.UpdateBookCategory
+	if err := decodeInput(args.Input, &input); err != nil {
+		return nil, err
+	}
+
+	res, err := r.strg.BookCategoryRepo().UpdateBookCategory(&input, id, actorFrom(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.categories([]models.BookCategory{res})[0], nil
+}
+
+func (r *resolver) DeleteBookCategory(ctx context.Context, args idArgs) (bool, error) {
+	id, err := entityID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := r.strg.BookCategoryRepo().DeleteBookCategory(id, actorFrom(ctx))
+	return rows > 0, err
+}
+
+// decodeInput fills dest from an input object and checks it with the binding rules of
+// the REST API.
+func decodeInput(input interface{}, dest interface{}) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(dest)
+}
+
+// entityID returns the id argument of a mutation, which must be an id rather than a slug.
+func entityID(id graphql.ID) (string, error) {
+	if !helper.IsID(string(id)) {
+		return "", fmt.Errorf("%q is not a valid id", id)
+	}
+	return string(id), nil
+}
+
+// batchIDs returns the ids argument of a list, checked like the ids of a REST batch
+// read, or nil if it is not given.
+func batchIDs(raw *[]graphql.ID) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	ids := make([]string, len(*raw))
+	for i, id := range *raw {
+		ids[i] = string(id)
+	}
+
+	return helper.ParseIDs(strings.Join(ids, ","))
+}
+
+// pageParams returns the search, limit and offset arguments of a list.
+func pageParams(args listArgs) (models.ApplicationQueryParamModel, error) {
+	var qP models.ApplicationQueryParamModel
+
+	limit, err := listLimit(args.Limit)
+	if err != nil {
+		return qP, err
+	}
+	qP.Limit = limit
+
+	qP.Offset = int(args.Offset)
+	if qP.Offset < 0 {
+		return qP, errors.New("offset cannot be negative")
+	}
+
+	qP.Search = stringValue(args.Search)
+
+	return qP, nil
+}
+
+func listLimit(limit int32) (int, error) {
+	if limit < 1 || limit > maxListLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+	}
+	return int(limit), nil
+}
+
+// distinct returns ids without repeats, in the order they were first given.
+func distinct(ids []string) []string {
+	var resp []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			resp = append(resp, id)
+		}
+	}
+	return resp
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intValue(n *int32) int {
+	if n == nil {
+		return 0
+	}
+	return int(*n)
+}
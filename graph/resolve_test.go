@@ -0,0 +1,239 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+	"github.com/saidakhmatov/catalog_of_books/storage"
+)
+
+// fakeStorage serves a few books, authors and categories, and counts the reads of each
+// kind.
+type fakeStorage struct {
+	storage.StorageI
+
+	mu    sync.Mutex
+	reads map[string]int
+
+	books      []models.Book
+	authors    []models.Author
+	categories []models.BookCategory
+}
+
+func (s *fakeStorage) read(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reads[name]++
+}
+
+func (s *fakeStorage) BookRepo() storage.BookI                 { return fakeBooks{fakeStorage: s} }
+func (s *fakeStorage) AuthorRepo() storage.AuthorI             { return fakeAuthors{fakeStorage: s} }
+func (s *fakeStorage) BookCategoryRepo() storage.BookCategoryI { return fakeCategories{fakeStorage: s} }
+
+type fakeBooks struct {
+	storage.BookI
+	*fakeStorage
+}
+
+func (s fakeBooks) GetAllBooks(queryParam models.BookQueryParamModel) ([]models.Book, error) {
+	s.read("GetAllBooks")
+	if queryParam.Limit < len(s.books) {
+		return s.books[:queryParam.Limit], nil
+	}
+	return s.books, nil
+}
+
+func (s fakeBooks) GetBooksByAuthorIDs(authorIDs []string, limit int, fields ...string) ([]models.Book, error) {
+	s.read("GetBooksByAuthorIDs")
+	return s.booksWhere(func(b models.Book) string { return b.AuthorID }, authorIDs, limit), nil
+}
+
+func (s fakeBooks) GetBooksBySeriesIDs(seriesIDs []string, limit int, fields ...string) ([]models.Book, error) {
+	s.read("GetBooksBySeriesIDs")
+	return s.booksWhere(func(b models.Book) string {
+		if b.SeriesID == nil {
+			return ""
+		}
+		return *b.SeriesID
+	}, seriesIDs, limit), nil
+}
+
+// booksWhere returns up to limit books of each of the ids.
+func (s fakeBooks) booksWhere(key func(models.Book) string, ids []string, limit int) []models.Book {
+	var resp []models.Book
+	for _, id := range ids {
+		n := 0
+		for _, book := range s.books {
+			if key(book) == id && n < limit {
+				resp = append(resp, book)
+				n++
+			}
+		}
+	}
+	return resp
+}
+
+type fakeAuthors struct {
+	storage.AuthorI
+	*fakeStorage
+}
+
+func (s fakeAuthors) GetAuthorsByIDs(ids []string, fields ...string) ([]models.Author, error) {
+	s.read("GetAuthorsByIDs")
+	var resp []models.Author
+	for _, author := range s.authors {
+		for _, id := range ids {
+			if author.ID == id {
+				resp = append(resp, author)
+			}
+		}
+	}
+	return resp, nil
+}
+
+type fakeCategories struct {
+	storage.BookCategoryI
+	*fakeStorage
+}
+
+func (s fakeCategories) GetBookCategoriesByIDs(ids []string, fields ...string) ([]models.BookCategory, error) {
+	s.read("GetBookCategoriesByIDs")
+	var resp []models.BookCategory
+	for _, category := range s.categories {
+		for _, id := range ids {
+			if category.ID == id {
+				resp = append(resp, category)
+			}
+		}
+	}
+	return resp, nil
+}
+
+func newFakeStorage() *fakeStorage {
+	series := "s1"
+	return &fakeStorage{
+		reads: make(map[string]int),
+		books: []models.Book{
+			{ID: "b1", BookName: "One", AuthorID: "a1", CategoryID: "c1", SeriesID: &series},
+			{ID: "b2", BookName: "Two", AuthorID: "a1", CategoryID: "c1", SeriesID: &series},
+			{ID: "b3", BookName: "Three", AuthorID: "a2", CategoryID: "c1"},
+		},
+		authors: []models.Author{
+			{ID: "a1", DisplayName: "First"},
+			{ID: "a2", DisplayName: "Second"},
+		},
+		categories: []models.BookCategory{
+			{ID: "c1", CategoryName: "Fiction"},
+		},
+	}
+}
+
+func TestExecBatchesLevels(t *testing.T) {
+	strg := newFakeStorage()
+	schema, err := NewSchema(strg, nil, Limits{MaxDepth: 8, MaxComplexity: 2000})
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+
+	result := schema.Exec(context.Background(), Params{Query: `{
+		books {
+			id
+			author { display_name books(limit: 5) { id } }
+			category { category_name }
+			siblings { id }
+		}
+	}`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Exec: %v", result.Errors)
+	}
+
+	want := `{"books":[` +
+		`{"id":"b1","author":{"display_name":"First","books":[{"id":"b1"},{"id":"b2"}]},"category":{"category_name":"Fiction"},"siblings":[{"id":"b2"}]},` +
+		`{"id":"b2","author":{"display_name":"First","books":[{"id":"b1"},{"id":"b2"}]},"category":{"category_name":"Fiction"},"siblings":[{"id":"b1"}]},` +
+		`{"id":"b3","author":{"display_name":"Second","books":[{"id":"b3"}]},"category":{"category_name":"Fiction"},"siblings":[]}]}`
+	if string(result.Data) != want {
+		t.Errorf("Exec = %s, want %s", result.Data, want)
+	}
+
+	// Every relation is read once per level, however many objects the level has.
+	for name, n := range map[string]int{
+		"GetAllBooks":            1,
+		"GetAuthorsByIDs":        1,
+		"GetBooksByAuthorIDs":    1,
+		"GetBookCategoriesByIDs": 1,
+		"GetBooksBySeriesIDs":    1,
+	} {
+		if strg.reads[name] != n {
+			t.Errorf("%s read %d times, want %d", name, strg.reads[name], n)
+		}
+	}
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string // the data as JSON
+		wantErr string // or part of the first error
+	}{
+		{
+			name:  "introspection",
+			query: `{ __type(name: "Book") { name fields { name } } }`,
+			want:  `"name":"siblings"`,
+		},
+		{
+			name:  "schema types",
+			query: `{ __schema { types { name } } }`,
+			want:  `{"name":"BookCategory"}`,
+		},
+		{
+			name:  "repeated fragment spread",
+			query: `{ books(limit: 1) { ...F ...F } } fragment F on Book { id }`,
+			want:  `{"books":[{"id":"b1"}]}`,
+		},
+		{
+			name:    "limit out of range",
+			query:   `{ books(limit: 0) { id } }`,
+			wantErr: "limit must be between 1 and",
+		},
+		{
+			name:    "invalid id of a mutation",
+			query:   `mutation { delete_book(id: "b1") }`,
+			wantErr: `"b1" is not a valid id`,
+		},
+		{
+			name:    "input checked like the REST API",
+			query:   `mutation { create_author(input: {firstname: "A", lastname: "B", nationality: "Narnia"}) { id } }`,
+			wantErr: "iso3166_1_alpha2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewSchema(newFakeStorage(), nil, Limits{MaxDepth: 8, MaxComplexity: 2000})
+			if err != nil {
+				t.Fatalf("NewSchema: %v", err)
+			}
+
+			result := schema.Exec(context.Background(), Params{Query: tt.query})
+
+			if len(tt.wantErr) > 0 {
+				if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, tt.wantErr) {
+					t.Fatalf("Exec = %v, want error containing %q", result.Errors, tt.wantErr)
+				}
+				return
+			}
+
+			if len(result.Errors) > 0 {
+				t.Fatalf("Exec: %v", result.Errors)
+			}
+			if !json.Valid(result.Data) || !strings.Contains(string(result.Data), tt.want) {
+				t.Errorf("Exec = %s, want it to contain %s", result.Data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package graph is the GraphQL schema of the catalog. Its resolvers read and write
+// through storage.StorageI like the REST handlers, and load the related records of a
+// whole level of a response in one batch.
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/storage"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed schema.graphql
+var schemaSource string
+
+// Params is a GraphQL request.
+type Params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Limits bound the operations a schema runs. A zero limit is not checked.
+type Limits struct {
+	MaxDepth      int // fields nested in one another, introspection left out
+	MaxComplexity int // fields counted once per object they are expected on
+}
+
+// Schema runs operations on the catalog.
+type Schema struct {
+	schema *graphql.Schema
+	ast    *ast.Schema // to measure operations before they run
+	limits Limits
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose mutations are recorded as made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// resolver resolves the fields of the Query and Mutation types.
+type resolver struct {
+	strg storage.StorageI
+	ids  helper.IDGenerator
+}
+
+// NewSchema returns the catalog schema: books, authors and categories with their
+// relations, and mutations to create, update and delete them.
+func NewSchema(strg storage.StorageI, ids helper.IDGenerator, limits Limits) (*Schema, error) {
+	schema, err := graphql.ParseSchema(schemaSource, &resolver{strg: strg, ids: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSource})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{schema: schema, ast: source, limits: limits}, nil
+}
+
+// Exec runs the operation of params. Operations over the limits of the schema are
+// rejected before they run, with a response without data.
+func (s *Schema) Exec(ctx context.Context, params Params) *graphql.Response {
+	if errs := s.check(params); len(errs) > 0 {
+		return &graphql.Response{Errors: errs}
+	}
+
+	return s.schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/saidakhmatov/catalog_of_books/dedupe"
 	"github.com/saidakhmatov/catalog_of_books/helper"
 
 	"github.com/gin-gonic/gin"
@@ -17,10 +20,12 @@ import (
 // @Description Create an author
 // @Tags        Author
 // @Router      /authors [POST]
+// @Param       X-Actor header string false "who makes the change, kept in the history"
 // @Accept      json
 // @Produce     json
 // @Param       author body     models.CreateAuthor true "Author Body"
-// @Success     201    {object} models.Response     "Success Response"
+// @Param       Prefer header   string              false "return=minimal to get only the new id"
+// @Success     201    {object} models.Response     "Created author, its URL in Location"
 // @Response    400    {object} models.Response     "Bad Request Error"
 func (h *handler) CreateAuthor(ctx *gin.Context) {
 	var ar models.CreateAuthor
@@ -36,16 +41,21 @@ func (h *handler) CreateAuthor(ctx *gin.Context) {
 		return
 	}
 
-	new_id := helper.UUIDMaker()
+	new_id := h.ids.NewID()
 	dt := time.Now()
 
 	new_ar.ID = new_id
 	new_ar.Firstname = ar.Firstname
 	new_ar.Lastname = ar.Lastname
+	new_ar.BirthDate = ar.BirthDate
+	new_ar.DeathDate = ar.DeathDate
+	new_ar.Nationality = ar.Nationality
+	new_ar.Biography = ar.Biography
+	new_ar.Aliases = ar.Aliases
 	new_ar.CreatedAt = dt
 	new_ar.UpdatedAt = dt
 
-	res, err := h.strg.AuthorRepo().CreateAuthor(new_ar)
+	res, err := h.strg.AuthorRepo().CreateAuthor(new_ar, requestActor(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"response": models.Response{
@@ -56,11 +66,18 @@ func (h *handler) CreateAuthor(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("Location", resourceLocation(ctx, res.ID))
+
+	var data interface{} = res
+	if preferMinimal(ctx) {
+		data = res.ID
+	}
+
 	ctx.JSON(http.StatusCreated, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "successfully created",
-			Data:    res,
+			Data:    data,
 		},
 	})
 }
@@ -70,14 +87,22 @@ func (h *handler) CreateAuthor(ctx *gin.Context) {
 // @Router   /authors [get]
 // @Tags     Author
 // @Produce  json
+// @Param    ids    query    string          false "comma separated ids to fetch, in order; other filters are ignored"
 // @Param    search query    string          false "Search Query"
 // @Param    limit  query    string          false "limit"
 // @Param    offset query    string          false "offset"
+// @Param    fields query    string          false "comma separated fields to return, e.g. id,firstname"
+// @Param    as_of  query    string          false "read the state at this time, RFC 3339 or YYYY-MM-DD"
 // @Success  200    {object} models.Response "Success Response"
 // @Response 404    {object} models.Response "Not found"
 func (h *handler) GetAllAuthors(ctx *gin.Context) {
 	var qP models.ApplicationQueryParamModel
 
+	if ids, ids_exists := ctx.GetQuery("ids"); ids_exists {
+		h.getAuthorsByIDs(ctx, ids)
+		return
+	}
+
 	offset, offset_exists := ctx.GetQuery("offset")
 	if offset_exists {
 		res_offset, err := strconv.Atoi(offset)
@@ -117,6 +142,22 @@ func (h *handler) GetAllAuthors(ctx *gin.Context) {
 		qP.Search = search
 	}
 
+	qP.Fields = helper.ParseFields(ctx.Query("fields"))
+
+	asOf, err := parseAsOf(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all authors",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	qP.AsOf = asOf
+
 	res, err := h.strg.AuthorRepo().GetAllAuthors(qP)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -129,11 +170,81 @@ func (h *handler) GetAllAuthors(ctx *gin.Context) {
 		return
 	}
 
+	data, err := helper.PickFields(res, qP.Fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all authors",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "Success",
-			Data:    res,
+			Data:    data,
+		},
+	})
+}
+
+// getAuthorsByIDs answers a list request carrying ids= with the authors having those ids, in
+// the requested order, and the ids that were not found. Other list filters do not apply.
+func (h *handler) getAuthorsByIDs(ctx *gin.Context, raw string) {
+	ids, err := helper.ParseIDs(raw)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting authors by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	fields := helper.ParseFields(ctx.Query("fields"))
+
+	res, err := h.strg.AuthorRepo().GetAuthorsByIDs(ids, fields...)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting authors by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	found := make([]string, len(res))
+	for i, item := range res {
+		found[i] = item.ID
+	}
+
+	data, err := helper.PickFields(res, fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting authors by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data: models.BatchResponse{
+				Items:    data,
+				NotFound: missingIDs(ids, found),
+			},
 		},
 	})
 }
@@ -143,13 +254,58 @@ func (h *handler) GetAllAuthors(ctx *gin.Context) {
 // @Tags     Author
 // @Router   /authors/{id} [get]
 // @Produce  json
-// @Param    id  path     string          true "Author ID"
+// @Param    id  path     string          true "Author ID or slug"
+// @Param    fields query string false "comma separated fields to return, e.g. id,firstname"
+// @Param    as_of  query string false "read the state at this time, RFC 3339 or YYYY-MM-DD"
 // @Success  200 {object} models.Response "Success Response"
 // @Response 400 {object} models.Response "Bad Request Error"
+// @Response 301 {object} models.Response "Author was merged or renamed, see Location"
 // @Response 404    {object} models.Response     "Not found"
 func (h *handler) GetAuthor(ctx *gin.Context) {
 	id := ctx.Param("id")
-	res, err := h.strg.AuthorRepo().GetAuthor(id)
+	fields := helper.ParseFields(ctx.Query("fields"))
+	asOf, err := parseAsOf(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting author",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	var res models.Author
+	if asOf.IsZero() {
+		res, err = h.strg.AuthorRepo().GetAuthor(id, fields...)
+	} else {
+		res, err = h.strg.AuthorRepo().GetAuthorAsOf(id, asOf, fields...)
+	}
+	if errors.Is(err, sql.ErrNoRows) && asOf.IsZero() {
+		if helper.IsID(id) {
+			if target, rerr := h.strg.AuthorRepo().GetAuthorRedirect(id); rerr == nil {
+				redirectPermanently(ctx, id, target)
+				return
+			}
+		}
+		if target, rerr := h.strg.AuthorRepo().GetSlugRedirect(id); rerr == nil {
+			redirectPermanently(ctx, id, target)
+			return
+		}
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting author",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	data, err := helper.PickFields(res, fields)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"response": models.Response{
@@ -165,7 +321,7 @@ func (h *handler) GetAuthor(ctx *gin.Context) {
 		"response": models.Response{
 			Error:   "false",
 			Message: "success",
-			Data:    res,
+			Data:    data,
 		},
 	})
 	return
@@ -175,11 +331,13 @@ func (h *handler) GetAuthor(ctx *gin.Context) {
 // @Tags     Author
 // @ID       update_author_id
 // @Router   /authors/{id} [put]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
 // @Accept   json
 // @Produce  json
 // @Param    id     path     string              true "Author ID"
 // @Param    author body     models.UpdateAuthor true "Update Model"
-// @Success  200    {object} models.Response     "Success Response"
+// @Param    Prefer header   string              false "return=minimal to get only the affected row count"
+// @Success  200    {object} models.Response     "Updated author"
 // @Response 400    {object} models.Response     "Bad Request Error"
 // @Response 404 {object} models.Response "Not found"
 func (h *handler) UpdateAuthor(ctx *gin.Context) {
@@ -197,7 +355,17 @@ func (h *handler) UpdateAuthor(ctx *gin.Context) {
 		return
 	}
 
-	res, err := h.strg.AuthorRepo().UpdateAuthor(ar, id)
+	res, err := h.strg.AuthorRepo().UpdateAuthor(ar, id, requestActor(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no author with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
 	if err != nil {
 		log.Println(err)
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -210,11 +378,16 @@ func (h *handler) UpdateAuthor(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
+	var data interface{} = res
+	if preferMinimal(ctx) {
+		data = 1
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "successfully updated",
-			Data:    res,
+			Data:    data,
 		},
 	})
 	return
@@ -223,6 +396,7 @@ func (h *handler) UpdateAuthor(ctx *gin.Context) {
 // @Summary  delete an author by id
 // @Tags     Author
 // @Router   /authors/{id} [delete]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
 // @ID       delete_author_id
 // @Param    id  path     string          true "Author ID"
 // @Success  200 {object} models.Response "Success Response"
@@ -231,7 +405,7 @@ func (h *handler) UpdateAuthor(ctx *gin.Context) {
 func (h *handler) DeleteAuthor(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	res, err := h.strg.AuthorRepo().DeleteAuthor(id)
+	res, err := h.strg.AuthorRepo().DeleteAuthor(id, requestActor(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"response": models.Response{
@@ -251,3 +425,137 @@ func (h *handler) DeleteAuthor(ctx *gin.Context) {
 		},
 	})
 }
+
+// @Summary     Find likely duplicate authors
+// @ID          get_author_duplicates
+// @Description Pairs of authors whose normalized names are similar, best matches first
+// @Tags        Author
+// @Router      /authors/duplicates [get]
+// @Produce     json
+// @Param       threshold query    number          false "Minimum similarity between 0 and 1, default 0.9"
+// @Param       limit     query    string          false "limit"
+// @Success     200       {object} models.Response "Success Response"
+// @Response    400       {object} models.Response "Bad Request Error"
+func (h *handler) GetAuthorDuplicates(ctx *gin.Context) {
+	threshold := dedupe.DefaultThreshold
+	if t, ok := ctx.GetQuery("threshold"); ok {
+		res_threshold, err := strconv.ParseFloat(t, 64)
+		if err != nil || res_threshold <= 0 || res_threshold > 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   "threshold must be a number in (0, 1]",
+					Message: "Error while finding duplicate authors",
+					Data:    nil,
+				},
+			})
+			return
+		}
+		threshold = res_threshold
+	}
+
+	limit := 50
+	if l, ok := ctx.GetQuery("limit"); ok {
+		res_limit, err := strconv.Atoi(l)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while finding duplicate authors",
+					Data:    nil,
+				},
+			})
+			return
+		}
+		limit = res_limit
+	}
+
+	authors, err := h.strg.AuthorRepo().GetAuthorNames()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while finding duplicate authors",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	res := dedupe.Candidates(authors, threshold)
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary     Merge authors
+// @ID          merge_authors
+// @Description Move every book of the source authors to this author, delete the sources and redirect their ids here
+// @Tags        Author
+// @Router      /authors/{id}/merge [post]
+// @Param       X-Actor header string false "who makes the change, kept in the history"
+// @Accept      json
+// @Produce     json
+// @Param       id     path     string              true "Target Author ID"
+// @Param       merge  body     models.MergeAuthors true "Source authors"
+// @Success     200    {object} models.Response     "Success Response"
+// @Response    400    {object} models.Response     "Bad Request Error"
+func (h *handler) MergeAuthors(ctx *gin.Context) {
+	var body models.MergeAuthors
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while merging authors",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	sourceIDs := []string{}
+	for _, sourceID := range body.SourceIDs {
+		if sourceID == id {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   "an author cannot be merged into itself",
+					Message: "Error while merging authors",
+					Data:    nil,
+				},
+			})
+			return
+		}
+		if !helper.Contains(sourceIDs, sourceID) {
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+	}
+
+	res, err := h.strg.AuthorRepo().MergeAuthors(id, sourceIDs, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while merging authors",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "successfully merged",
+			Data:    res,
+		},
+	})
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -16,10 +18,12 @@ import (
 // @Description has no relation with others
 // @Tags        Book
 // @Router      /books [post]
+// @Param       X-Actor header string false "who makes the change, kept in the history"
 // @Accept      json
 // @Param       author body models.CreateBook true "Book Body"
+// @Param       Prefer header string false "return=minimal to get only the new id"
 // @Produce     json
-// @Success     201 {object} models.Response "Success Response"
+// @Success     201 {object} models.Response "Created book, its URL in Location"
 // @Response    400 {object} models.Response "Bad Request Error"
 func (h *handler) CreateBook(ctx *gin.Context) {
 	
@@ -38,7 +42,7 @@ func (h *handler) CreateBook(ctx *gin.Context) {
 	}
 
 	dt := time.Now()
-	new_id := helper.UUIDMaker()
+	new_id := h.ids.NewID()
 
 	book.ID = new_id
 	book.CreatedAt = dt
@@ -46,8 +50,14 @@ func (h *handler) CreateBook(ctx *gin.Context) {
 	book.CategoryID = bookCreate.CategoryID
 	book.AuthorID = bookCreate.AuthorID
 	book.BookName = bookCreate.BookName
+	book.PublicationYear = bookCreate.PublicationYear
+	book.Language = bookCreate.Language
+	book.PageCount = bookCreate.PageCount
+	book.Description = bookCreate.Description
+	book.Edition = bookCreate.Edition
+	book.Format = bookCreate.Format
 
-	res, err := h.strg.BookRepo().CreateBook(book)
+	res, err := h.strg.BookRepo().CreateBook(book, requestActor(ctx))
 	
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -59,11 +69,18 @@ func (h *handler) CreateBook(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("Location", resourceLocation(ctx, res.ID))
+
+	var data interface{} = res
+	if preferMinimal(ctx) {
+		data = res.ID
+	}
+
 	ctx.JSON(http.StatusCreated, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "Successfully created",
-			Data:    res,
+			Data:    data,
 		},
 	})
 }
@@ -73,14 +90,27 @@ func (h *handler) CreateBook(ctx *gin.Context) {
 // @Router   /books [get]
 // @Tags     Book
 // @Produce  json
-// @Param    search query    string          false "search"
-// @Param    limit  query    string          false "limit"
-// @Param    offset query    string          false "offset"
-// @Success  200    {object} models.Response "Success Response"
-// @Response 404    {object} models.Response "Some bad request"
+// @Param    ids       query    string          false "comma separated ids to fetch, in order; other filters are ignored"
+// @Param    search    query    string          false "search"
+// @Param    limit     query    string          false "limit"
+// @Param    offset    query    string          false "offset"
+// @Param    language  query    string          false "ISO 639-1 language code"
+// @Param    format    query    string          false "format" Enums(hardcover, paperback, ebook, audiobook)
+// @Param    year_from query    int             false "published in or after this year"
+// @Param    year_to   query    int             false "published in or before this year"
+// @Param    sort      query    string          false "sort field, prefix with - for descending, e.g. -average_rating"
+// @Param    fields    query    string          false "comma separated fields to return, e.g. id,book_name"
+// @Param    as_of     query    string          false "read the state at this time, RFC 3339 or YYYY-MM-DD"
+// @Success  200       {object} models.Response "Success Response"
+// @Response 404       {object} models.Response "Some bad request"
 func (h *handler) GetAllBooks(ctx *gin.Context) {
 	
-	var qP models.ApplicationQueryParamModel
+	var qP models.BookQueryParamModel
+
+	if ids, ids_exists := ctx.GetQuery("ids"); ids_exists {
+		h.getBooksByIDs(ctx, ids)
+		return
+	}
 
 	offset, offset_exists := ctx.GetQuery("offset")
 	
@@ -124,6 +154,98 @@ func (h *handler) GetAllBooks(ctx *gin.Context) {
 		qP.Search = search
 	}
 
+	language, language_exists := ctx.GetQuery("language")
+
+	if language_exists {
+		if !helper.IsLanguageCode(language) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   "language must be an ISO 639-1 code",
+					Message: "Error while getting all books",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Language = language
+	}
+
+	format, format_exists := ctx.GetQuery("format")
+
+	if format_exists {
+		if !helper.Contains(models.BookFormats, format) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   "unknown book format",
+					Message: "Error while getting all books",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Format = format
+	}
+
+	yearFrom, year_from_exists := ctx.GetQuery("year_from")
+
+	if year_from_exists {
+		res_year_from, err := strconv.Atoi(yearFrom)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all books",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.YearFrom = res_year_from
+	}
+
+	yearTo, year_to_exists := ctx.GetQuery("year_to")
+
+	if year_to_exists {
+		res_year_to, err := strconv.Atoi(yearTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all books",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.YearTo = res_year_to
+	}
+
+	sort, sort_exists := ctx.GetQuery("sort")
+
+	if sort_exists {
+		qP.Sort = sort
+	}
+
+	qP.Fields = helper.ParseFields(ctx.Query("fields"))
+
+	asOf, err := parseAsOf(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all books",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	qP.AsOf = asOf
+
 	books, err := h.strg.BookRepo().GetAllBooks(qP)
 	
 	if err != nil {
@@ -137,11 +259,81 @@ func (h *handler) GetAllBooks(ctx *gin.Context) {
 		return
 	}
 
+	data, err := helper.PickFields(books, qP.Fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all books",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "Success",
-			Data:    books,
+			Data:    data,
+		},
+	})
+}
+
+// getBooksByIDs answers a list request carrying ids= with the books having those ids, in
+// the requested order, and the ids that were not found. Other list filters do not apply.
+func (h *handler) getBooksByIDs(ctx *gin.Context, raw string) {
+	ids, err := helper.ParseIDs(raw)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting books by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	fields := helper.ParseFields(ctx.Query("fields"))
+
+	books, err := h.strg.BookRepo().GetBooksByIDs(ids, fields...)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting books by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	found := make([]string, len(books))
+	for i, item := range books {
+		found[i] = item.ID
+	}
+
+	data, err := helper.PickFields(books, fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting books by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data: models.BatchResponse{
+				Items:    data,
+				NotFound: missingIDs(ids, found),
+			},
 		},
 	})
 }
@@ -151,15 +343,42 @@ func (h *handler) GetAllBooks(ctx *gin.Context) {
 // @Tags     Book
 // @Router   /books/{id} [get]
 // @Produce  json
-// @Param    id  path     string          true "Book Category ID"
+// @Param    id  path     string          true "Book ID or slug"
+// @Param    fields query string false "comma separated fields to return, e.g. id,book_name"
+// @Param    as_of  query string false "read the state at this time, RFC 3339 or YYYY-MM-DD"
 // @Success  200 {object} models.Response "Success Response"
 // @Response 400 {object} models.Response "Bad Request Error"
+// @Response 301 {object} models.Response "Book was renamed, see Location"
 // @Response 404  {object} models.Response   "Not found"
 func (h *handler) GetBook(ctx *gin.Context) {
 	
 	id := ctx.Param("id")
+	fields := helper.ParseFields(ctx.Query("fields"))
+
+	asOf, err := parseAsOf(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while author getting a book",
+				Data:    nil,
+			},
+		})
+		return
+	}
 
-	res, err := h.strg.BookRepo().GetBook(id)
+	var res models.Book
+	if asOf.IsZero() {
+		res, err = h.strg.BookRepo().GetBook(id, fields...)
+	} else {
+		res, err = h.strg.BookRepo().GetBookAsOf(id, asOf, fields...)
+	}
+	if errors.Is(err, sql.ErrNoRows) && asOf.IsZero() {
+		if target, rerr := h.strg.BookRepo().GetSlugRedirect(id); rerr == nil {
+			redirectPermanently(ctx, id, target)
+			return
+		}
+	}
 	
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -172,11 +391,23 @@ func (h *handler) GetBook(ctx *gin.Context) {
 		return
 	}
 
+	data, err := helper.PickFields(res, fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while author getting a book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "Success",
-			Data:    res,
+			Data:    data,
 		},
 	})
 }
@@ -185,11 +416,13 @@ func (h *handler) GetBook(ctx *gin.Context) {
 // @Tags     Book
 // @ID       update_book_id
 // @Router   /books/{id} [put]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
 // @Accept   json
 // @Produce  json
 // @Param    id   path     string            true "Book ID"
 // @Param    book body     models.UpdateBook true "Update Model"
-// @Success  200  {object} models.Response   "SUccess REsponse"
+// @Param    Prefer header string false "return=minimal to get only the affected row count"
+// @Success  200  {object} models.Response   "Updated book"
 // @Response 400  {object} models.Response   "Bad Request Error"
 // @Response 404 {object} models.Response "Not found"
 func (h *handler) UpdateBook(ctx *gin.Context) {
@@ -208,7 +441,17 @@ func (h *handler) UpdateBook(ctx *gin.Context) {
 		return
 	}
 
-	res, err := h.strg.BookRepo().UpdateBook(bookModel, id)
+	res, err := h.strg.BookRepo().UpdateBook(bookModel, id, requestActor(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no book with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
 	
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -220,6 +463,70 @@ func (h *handler) UpdateBook(ctx *gin.Context) {
 		return
 	}
 
+	var data interface{} = res
+	if preferMinimal(ctx) {
+		data = 1
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    data,
+		},
+	})
+}
+
+// @Summary  Revert a book to a previous version
+// @Tags     Book
+// @ID       revert_book_id
+// @Router   /books/{id}/revert [post]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
+// @Produce  json
+// @Param    id      path     string          true "Book ID"
+// @Param    version query    int             true "version from the book's history"
+// @Success  200     {object} models.Response "Reverted book"
+// @Response 400     {object} models.Response "Bad Request Error"
+// @Response 404     {object} models.Response "Not found"
+func (h *handler) RevertBook(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+
+	version, err := strconv.Atoi(ctx.Query("version"))
+	if err != nil || version < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   "version must be a positive integer",
+				Message: "Error while reverting book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.BookRepo().RevertBook(id, version, requestActor(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no book with the given id and version",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while reverting book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"response": models.Response{
 			Error:   "false",
@@ -232,6 +539,7 @@ func (h *handler) UpdateBook(ctx *gin.Context) {
 // @Summary  delete an book by id
 // @Tags     Book
 // @Router   /books/{id} [delete]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
 // @ID       delete_book_id
 // @Param    id  path     string          true "Book ID"
 // @Success  200 {object} models.Response "Success Response"
@@ -241,7 +549,7 @@ func (h *handler) DeleteBook(ctx *gin.Context) {
 	
 	id := ctx.Param("id")
 
-	res, err := h.strg.BookRepo().DeleteBook(id)
+	res, err := h.strg.BookRepo().DeleteBook(id, requestActor(ctx))
 	
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -1,13 +1,14 @@
 package handler
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/saidakhmatov/catalog_of_books/helper"
-
 	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/helper"
 	"github.com/saidakhmatov/catalog_of_books/models"
 )
 
@@ -16,10 +17,12 @@ import (
 // @Description has no relation with others
 // @Tags        BookCategory
 // @Router      /book_category [POST]
+// @Param       X-Actor header string false "who makes the change, kept in the history"
 // @Accept      json
 // @Param       author body models.CreateBookCategory true "Author Body"
+// @Param       Prefer header string false "return=minimal to get only the new id"
 // @Produce     json
-// @Success     201 {object} models.Response "Success Response"
+// @Success     201 {object} models.Response "Created category, its URL in Location"
 // @Response    400 {object} models.Response "Bad Request Error"
 func (h *handler) CreateBookCategory(ctx *gin.Context) {
 	var bookCatCreate *models.CreateBookCategory
@@ -37,12 +40,12 @@ func (h *handler) CreateBookCategory(ctx *gin.Context) {
 
 	dt := time.Now()
 
-	bookCat.ID = helper.UUIDMaker()
+	bookCat.ID = h.ids.NewID()
 	bookCat.CategoryName = bookCatCreate.CategoryName
 	bookCat.CreatedAt = dt
 	bookCat.UpdatedAt = dt
 
-	res, err := h.strg.BookCategoryRepo().CreateBookCategory(bookCat)
+	res, err := h.strg.BookCategoryRepo().CreateBookCategory(bookCat, requestActor(ctx))
 	
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -54,11 +57,18 @@ func (h *handler) CreateBookCategory(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("Location", resourceLocation(ctx, res.ID))
+
+	var data interface{} = res
+	if preferMinimal(ctx) {
+		data = res.ID
+	}
+
 	ctx.JSON(http.StatusCreated, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "Successfully Created",
-			Data:    res,
+			Data:    data,
 		},
 	})
 }
@@ -68,14 +78,22 @@ func (h *handler) CreateBookCategory(ctx *gin.Context) {
 // @Router   /book_category [GET]
 // @Tags     BookCategory
 // @Produce  json
+// @Param    ids    query    string          false "comma separated ids to fetch, in order; other filters are ignored"
 // @Param    search query    string          false "Search Query"
 // @Param    limit  query    string          false "limit"
 // @Param    offset query    string          false "offset"
+// @Param    fields query    string          false "comma separated fields to return, e.g. id,category_name"
+// @Param    as_of  query    string          false "read the state at this time, RFC 3339 or YYYY-MM-DD"
 // @Success  200    {object} models.Response "Success Response"
 // @Response 404    {object} models.Response "Bad Request Error"
 func (h *handler) GetAllBookCategories(ctx *gin.Context) {
 	var qP models.ApplicationQueryParamModel
 
+	if ids, ids_exists := ctx.GetQuery("ids"); ids_exists {
+		h.getBookCategoriesByIDs(ctx, ids)
+		return
+	}
+
 	offset, offset_exists := ctx.GetQuery("offset")
 
 	if offset_exists {
@@ -118,6 +136,22 @@ func (h *handler) GetAllBookCategories(ctx *gin.Context) {
 		qP.Search = search
 	}
 
+	qP.Fields = helper.ParseFields(ctx.Query("fields"))
+
+	asOf, err := parseAsOf(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all categories",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	qP.AsOf = asOf
+
 	bookCats, err := h.strg.BookCategoryRepo().GetAllBookCategories(qP)
 
 	if err != nil {
@@ -132,12 +166,82 @@ func (h *handler) GetAllBookCategories(ctx *gin.Context) {
 		return
 	}
 
+	data, err := helper.PickFields(bookCats, qP.Fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all categories",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 
 		"response": models.Response{
 			Error:   "false",
 			Message: "Success",
-			Data:    bookCats,
+			Data:    data,
+		},
+	})
+}
+
+// getBookCategoriesByIDs answers a list request carrying ids= with the categories having those ids, in
+// the requested order, and the ids that were not found. Other list filters do not apply.
+func (h *handler) getBookCategoriesByIDs(ctx *gin.Context, raw string) {
+	ids, err := helper.ParseIDs(raw)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting categories by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	fields := helper.ParseFields(ctx.Query("fields"))
+
+	bookCats, err := h.strg.BookCategoryRepo().GetBookCategoriesByIDs(ids, fields...)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting categories by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	found := make([]string, len(bookCats))
+	for i, item := range bookCats {
+		found[i] = item.ID
+	}
+
+	data, err := helper.PickFields(bookCats, fields)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting categories by ids",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data: models.BatchResponse{
+				Items:    data,
+				NotFound: missingIDs(ids, found),
+			},
 		},
 	})
 }
@@ -147,14 +251,54 @@ func (h *handler) GetAllBookCategories(ctx *gin.Context) {
 // @Tags     BookCategory
 // @Router   /book_category/{id} [get]
 // @Produce  json
-// @Param    id  path     string          true "Book Category ID"
+// @Param    id  path     string          true "Book Category ID or slug"
+// @Param    fields query string false "comma separated fields to return, e.g. id,category_name"
+// @Param    as_of  query string false "read the state at this time, RFC 3339 or YYYY-MM-DD"
 // @Success  200 {object} models.Response "Success Response"
 // @Response 400 {object} models.Response "Bad Request Error"
+// @Response 301 {object} models.Response "Category was renamed, see Location"
 // @Response 404    {object} models.Response           "Not found"
 func (h *handler) GetBookCategory(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	res, err := h.strg.BookCategoryRepo().GetBookCategory(id)
+	fields := helper.ParseFields(ctx.Query("fields"))
+
+	asOf, err := parseAsOf(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting book category",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	var res models.BookCategory
+	if asOf.IsZero() {
+		res, err = h.strg.BookCategoryRepo().GetBookCategory(id, fields...)
+	} else {
+		res, err = h.strg.BookCategoryRepo().GetBookCategoryAsOf(id, asOf, fields...)
+	}
+	if errors.Is(err, sql.ErrNoRows) && asOf.IsZero() {
+		if target, rerr := h.strg.BookCategoryRepo().GetSlugRedirect(id); rerr == nil {
+			redirectPermanently(ctx, id, target)
+			return
+		}
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting book category",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	data, err := helper.PickFields(res, fields)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"response": models.Response{
@@ -170,7 +314,7 @@ func (h *handler) GetBookCategory(ctx *gin.Context) {
 		"response": models.Response{
 			Error:   "false",
 			Message: "Success",
-			Data:    res,
+			Data:    data,
 		},
 	})
 }
@@ -179,11 +323,13 @@ func (h *handler) GetBookCategory(ctx *gin.Context) {
 // @Tags     BookCategory
 // @ID       update_author_id
 // @Router   /book_category/{id} [put]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
 // @Accept   json
 // @Produce  json
 // @Param    id     path     string                    true "Book Category ID"
 // @Param    author body     models.UpdateBookCategory true "Update Model"
-// @Success  200    {object} models.Response           "Success Response"
+// @Param    Prefer header   string                    false "return=minimal to get only the affected row count"
+// @Success  200    {object} models.Response           "Updated category"
 // @Response 400    {object} models.Response           "Bad Request Error"
 // @Response 404 {object} models.Response "Not found"
 func (h *handler) UpdateBookCategory(ctx *gin.Context) {
@@ -200,7 +346,17 @@ func (h *handler) UpdateBookCategory(ctx *gin.Context) {
 		return
 	}
 
-	res, err := h.strg.BookCategoryRepo().UpdateBookCategory(bookCatModel, id)
+	res, err := h.strg.BookCategoryRepo().UpdateBookCategory(bookCatModel, id, requestActor(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no book category with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"response": models.Response{
@@ -211,11 +367,16 @@ func (h *handler) UpdateBookCategory(ctx *gin.Context) {
 		return
 	}
 
+	var data interface{} = res
+	if preferMinimal(ctx) {
+		data = 1
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"response": models.Response{
 			Error:   "false",
 			Message: "Success",
-			Data:    res,
+			Data:    data,
 		},
 	})
 }
@@ -223,6 +384,7 @@ func (h *handler) UpdateBookCategory(ctx *gin.Context) {
 // @Summary  delete an book category by id
 // @Tags     BookCategory
 // @Router   /book_category/{id} [delete]
+// @Param    X-Actor header string false "who makes the change, kept in the history"
 // @ID       delete_book_category_id
 // @Param    id  path     string          true "Book Category ID"
 // @Success  200 {object} models.Response "Success Response"
@@ -231,7 +393,7 @@ func (h *handler) UpdateBookCategory(ctx *gin.Context) {
 func (h *handler) DeleteBookCategory(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	res, err := h.strg.BookCategoryRepo().DeleteBookCategory(id)
+	res, err := h.strg.BookCategoryRepo().DeleteBookCategory(id, requestActor(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"response": models.Response{
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Add a physical copy of a book
+// @ID          create_copy_id
+// @Description barcode must be unique across the library
+// @Tags        Copy
+// @Router      /books/{id}/copies [POST]
+// @Accept      json
+// @Param       id   path string            true "Book ID"
+// @Param       copy body models.CreateCopy true "Copy Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateCopy(ctx *gin.Context) {
+	var copyCreate models.CreateCopy
+	var bookCopy models.Copy
+
+	if err := ctx.ShouldBindJSON(&copyCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	dt := time.Now()
+
+	bookCopy.ID = h.ids.NewID()
+	bookCopy.BookID = ctx.Param("id")
+	bookCopy.Barcode = copyCreate.Barcode
+	bookCopy.Condition = copyCreate.Condition
+	bookCopy.ShelfLocation = copyCreate.ShelfLocation
+	bookCopy.AcquisitionDate = copyCreate.AcquisitionDate
+	bookCopy.Status = copyCreate.Status
+	bookCopy.CreatedAt = dt
+	bookCopy.UpdatedAt = dt
+
+	if len(bookCopy.Condition) == 0 {
+		bookCopy.Condition = models.CopyConditionGood
+	}
+
+	if len(bookCopy.Status) == 0 {
+		bookCopy.Status = models.CopyStatusAvailable
+	}
+
+	res, err := h.strg.CopyRepo().CreateCopy(bookCopy, h.cfg.HoldPickupPeriod())
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully Created",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get all copies of a book
+// @ID       get_all_copies_id
+// @Router   /books/{id}/copies [GET]
+// @Tags     Copy
+// @Produce  json
+// @Param    id     path     string          true  "Book ID"
+// @Param    search query    string          false "barcode or shelf location"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Success Response"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetAllCopies(ctx *gin.Context) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all copies",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all copies",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	search, search_exists := ctx.GetQuery("search")
+
+	if search_exists {
+		qP.Search = search
+	}
+
+	res, err := h.strg.CopyRepo().GetAllCopies(ctx.Param("id"), qP)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all copies",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get a copy of a book by ID
+// @ID       get_copy_id
+// @Tags     Copy
+// @Router   /books/{id}/copies/{copy_id} [get]
+// @Produce  json
+// @Param    id      path     string          true "Book ID"
+// @Param    copy_id path     string          true "Copy ID"
+// @Success  200     {object} models.Response "Success Response"
+// @Response 400     {object} models.Response "Bad Request Error"
+func (h *handler) GetCopy(ctx *gin.Context) {
+	res, err := h.strg.CopyRepo().GetCopy(ctx.Param("id"), ctx.Param("copy_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting copy",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Look up a copy by its barcode
+// @ID       get_copy_by_barcode_id
+// @Tags     Copy
+// @Router   /copies/barcode/{barcode} [get]
+// @Produce  json
+// @Param    barcode path     string          true "Barcode"
+// @Success  200     {object} models.Response "Success Response"
+// @Response 400     {object} models.Response "Bad Request Error"
+func (h *handler) GetCopyByBarcode(ctx *gin.Context) {
+	res, err := h.strg.CopyRepo().GetCopyByBarcode(ctx.Param("barcode"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting copy",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Update a copy of a book
+// @Tags     Copy
+// @ID       update_copy_id
+// @Router   /books/{id}/copies/{copy_id} [put]
+// @Accept   json
+// @Produce  json
+// @Param    id      path     string            true "Book ID"
+// @Param    copy_id path     string            true "Copy ID"
+// @Param    copy    body     models.UpdateCopy true "Update Model"
+// @Success  200     {object} models.Response   "Success Response"
+// @Response 400     {object} models.Response   "Bad Request Error"
+func (h *handler) UpdateCopy(ctx *gin.Context) {
+	var copyModel models.UpdateCopy
+
+	if err := ctx.ShouldBindJSON(&copyModel); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.CopyRepo().UpdateCopy(copyModel, ctx.Param("id"), ctx.Param("copy_id"), h.cfg.HoldPickupPeriod())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  delete a copy of a book
+// @Tags     Copy
+// @Router   /books/{id}/copies/{copy_id} [delete]
+// @ID       delete_copy_id
+// @Param    id      path     string          true "Book ID"
+// @Param    copy_id path     string          true "Copy ID"
+// @Success  200     {object} models.Response "Success Response"
+// @Response 400     {object} models.Response "Bad Request Error"
+func (h *handler) DeleteCopy(ctx *gin.Context) {
+	res, err := h.strg.CopyRepo().DeleteCopy(ctx.Param("id"), ctx.Param("copy_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while deleting copy",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
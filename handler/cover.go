@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/helper"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// coverWidths lists the thumbnail sizes generated for every uploaded cover.
+var coverWidths = map[string]int{
+	models.CoverSizeMedium: 300,
+	models.CoverSizeSmall:  100,
+}
+
+// Covers are decoded in full to make thumbnails, which takes memory in proportion to
+// their pixels rather than their bytes, so their dimensions are capped as well.
+const (
+	coverMaxSide   = 10000
+	coverMaxPixels = 40000000
+)
+
+// coverKey is the key of one size of a cover. Every upload is stored under its own ETag,
+// so the images of a cover are only replaced by pointing its row at new ones.
+func coverKey(bookID, etag, size string) string {
+	return fmt.Sprintf("covers/%s/%s/%s", bookID, etag, size)
+}
+
+// deleteCoverBlobs removes the images of a cover that is not, or no longer, in use. A
+// failure only leaves unused blobs behind, so it is not reported.
+func (h *handler) deleteCoverBlobs(bookID, etag string, sizes []string) {
+	for _, size := range sizes {
+		h.blobs.Delete(coverKey(bookID, etag, size))
+	}
+}
+
+func encodeCover(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	}
+
+	return buf.Bytes(), err
+}
+
+// @Summary     Upload a book cover
+// @ID          upload_book_cover_id
+// @Description accepts a JPEG or PNG image and generates medium and small thumbnails
+// @Tags        Book
+// @Router      /books/{id}/cover [put]
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       id    path     string          true "Book ID"
+// @Param       cover formData file            true "Cover image"
+// @Success     200   {object} models.Response "Success Response"
+// @Response    400   {object} models.Response "Bad Request Error"
+// @Response    413   {object} models.Response "Image too large"
+// @Response    415   {object} models.Response "Unsupported image type"
+func (h *handler) UploadCover(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if _, err := h.strg.BookRepo().GetBook(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	// leave room for the multipart envelope around the image itself
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.cfg.CoverMaxBytes+1<<20)
+
+	file, header, err := ctx.Request.FormFile("cover")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "cover file is required",
+				Data:    nil,
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.cfg.CoverMaxBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"response": models.Response{
+				Error:   fmt.Sprintf("cover must not exceed %d bytes", h.cfg.CoverMaxBytes),
+				Message: "Error while uploading cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, h.cfg.CoverMaxBytes+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while uploading cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"response": models.Response{
+				Error:   "cover must be a JPEG or PNG image",
+				Message: "Error while uploading cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while decoding cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if imgConfig.Width > coverMaxSide || imgConfig.Height > coverMaxSide || imgConfig.Width*imgConfig.Height > coverMaxPixels {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"response": models.Response{
+				Error:   fmt.Sprintf("cover must not exceed %d pixels a side or %d pixels in total", coverMaxSide, coverMaxPixels),
+				Message: "Error while uploading cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while decoding cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	// Every image is made before any is stored, and the row is switched to the new images
+	// only once they are all stored, so a failure leaves the previous cover as it was.
+	images := map[string][]byte{models.CoverSizeOriginal: data}
+	for size, width := range coverWidths {
+		thumbnail, err := encodeCover(helper.Thumbnail(img, width), contentType)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while making cover thumbnail",
+					Data:    nil,
+				},
+			})
+			return
+		}
+		images[size] = thumbnail
+	}
+
+	previous, err := h.strg.CoverRepo().GetCover(id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+	hadCover := err == nil
+
+	sum := sha256.Sum256(data)
+
+	cover := models.Cover{
+		BookID:      id,
+		ContentType: contentType,
+		ETag:        hex.EncodeToString(sum[:8]),
+		Size:        int64(len(data)),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// The same image uploaded again is stored under the keys already in use, which must
+	// not be deleted when something fails.
+	replacing := !hadCover || previous.ETag != cover.ETag
+
+	var stored []string
+	for size, blob := range images {
+		if err := h.blobs.Put(coverKey(id, cover.ETag, size), blob); err != nil {
+			if replacing {
+				h.deleteCoverBlobs(id, cover.ETag, stored)
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while storing cover",
+					Data:    nil,
+				},
+			})
+			return
+		}
+		stored = append(stored, size)
+	}
+
+	if err := h.strg.CoverRepo().UpsertCover(cover); err != nil {
+		if replacing {
+			h.deleteCoverBlobs(id, cover.ETag, stored)
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while saving cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if hadCover && replacing {
+		h.deleteCoverBlobs(id, previous.ETag, stored)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    cover,
+		},
+	})
+}
+
+// @Summary  Get a book cover
+// @ID       get_book_cover_id
+// @Tags     Book
+// @Router   /books/{id}/cover [get]
+// @Produce  image/jpeg,image/png
+// @Param    id   path     string          true  "Book ID"
+// @Param    size query    string          false "size" Enums(original, medium, small)
+// @Success  200  {file}   file            "Cover image"
+// @Response 304  {string} string          "Not modified"
+// @Response 400  {object} models.Response "Bad Request Error"
+// @Response 404  {object} models.Response "Not found"
+func (h *handler) GetCover(ctx *gin.Context) {
+	id := ctx.Param("id")
+	size := ctx.DefaultQuery("size", models.CoverSizeOriginal)
+
+	if _, ok := coverWidths[size]; !ok && size != models.CoverSizeOriginal {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   "size must be one of original, medium, small",
+				Message: "Error while getting cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	cover, err := h.strg.CoverRepo().GetCover(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "book has no cover",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	etag := fmt.Sprintf(`"%s-%s"`, cover.ETag, size)
+
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age=86400")
+	ctx.Header("Last-Modified", cover.UpdatedAt.UTC().Format(http.TimeFormat))
+
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	data, err := h.blobs.Get(coverKey(id, cover.ETag, size))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while reading cover",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.Data(http.StatusOK, cover.ContentType, data)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+const (
+	// eventBatchSize caps how many events are read from the outbox at once.
+	eventBatchSize = 100
+	// eventPollInterval is how often an idle stream looks for new events it was not
+	// notified of, e.g. while the event listener reconnects.
+	eventPollInterval = 5 * time.Second
+	// eventKeepAliveInterval is how often an idle stream sends a comment so proxies do
+	// not close it.
+	eventKeepAliveInterval = 15 * time.Second
+)
+
+// @Summary     Stream catalog changes
+// @ID          stream_events_id
+// @Description Server-Sent Events of created, updated and deleted books, authors and categories. Every event carries its sequence number as id, and the data is the same JSON webhooks receive. A client reconnecting with Last-Event-ID gets the events it missed; without it the stream starts at new events.
+// @Tags        Event
+// @Router      /events [get]
+// @Produce     text/event-stream
+// @Param       entity        query  string false "comma separated entities to stream, e.g. book,author" Enums(book, author, book_category)
+// @Param       Last-Event-ID header string false "id of the last event received"
+// @Success     200 {object} models.Event "Event stream"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) StreamEvents(ctx *gin.Context) {
+	entities := helper.ParseFields(ctx.Query("entity"))
+	for _, entity := range entities {
+		if !helper.Contains(models.EventEntities, entity) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   fmt.Sprintf("unknown entity %q, must be one of %v", entity, models.EventEntities),
+					Message: "Error while streaming events",
+					Data:    nil,
+				},
+			})
+			return
+		}
+	}
+
+	lastID, err := h.lastEventID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while streaming events",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.Header("Content-Type", sse.ContentType)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	// Any notification only wakes the stream up; the events themselves are read from the
+	// outbox in order.
+	notifications := h.events.Subscribe(1)
+	defer notifications.Close()
+
+	poll := time.NewTicker(eventPollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		events, err := h.strg.EventRepo().GetEvents(lastID, entities, eventBatchSize)
+		if err != nil {
+			// The client reconnects and resumes from the last event it got.
+			log.Println("Could not read events:", err)
+			return
+		}
+
+		for _, event := range events {
+			err := sse.Encode(ctx.Writer, sse.Event{
+				Id:    strconv.FormatInt(event.ID, 10),
+				Event: event.Type,
+				Data:  string(event.Payload),
+			})
+			if err != nil {
+				return
+			}
+			lastID = event.ID
+		}
+
+		if len(events) > 0 {
+			ctx.Writer.Flush()
+			keepAlive.Reset(eventKeepAliveInterval)
+		}
+		if len(events) == eventBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-notifications.C:
+		case <-poll.C:
+		case <-keepAlive.C:
+			if _, err := io.WriteString(ctx.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+// lastEventID returns the id of the event a stream starts after: the Last-Event-ID header
+// a reconnecting EventSource sends, or the latest event for a new stream.
+func (h *handler) lastEventID(ctx *gin.Context) (int64, error) {
+	raw := ctx.GetHeader("Last-Event-ID")
+	if len(raw) == 0 {
+		return h.strg.EventRepo().LatestEventID()
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("Last-Event-ID must be an event id, got %q", raw)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary  Get the fine ledger and balance of a member
+// @ID       get_member_fines_id
+// @Tags     Fine
+// @Router   /members/{id}/fines [get]
+// @Produce  json
+// @Param    id     path     string          true  "Member ID"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Success Response"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetMemberFines(ctx *gin.Context) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting fines",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting fines",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	res, err := h.strg.FineRepo().GetMemberFines(ctx.Param("id"), qP)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting fines",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary     Record a fine payment or waiver
+// @ID          create_member_fine_id
+// @Description the amount may not exceed the outstanding balance
+// @Tags        Fine
+// @Router      /members/{id}/fines [POST]
+// @Accept      json
+// @Param       id   path string            true "Member ID"
+// @Param       fine body models.CreateFine true "Fine Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateMemberFine(ctx *gin.Context) {
+	var fineCreate models.CreateFine
+	var fine models.Fine
+
+	if err := ctx.ShouldBindJSON(&fineCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	fine.ID = h.ids.NewID()
+	fine.MemberID = ctx.Param("id")
+	fine.Kind = fineCreate.Kind
+	fine.Amount = fineCreate.Amount
+	fine.Note = fineCreate.Note
+	fine.CreatedAt = time.Now()
+
+	res, err := h.strg.FineRepo().CreateFine(fine)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while recording fine",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully Created",
+			Data:    res,
+		},
+	})
+}
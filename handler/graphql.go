@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/graph"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Run a GraphQL query or mutation
+// @ID          graphql_id
+// @Description Queries and mutations of books, authors and categories, which can select their author, category, books and the sibling books of their series. Operations deeper or more complex than configured are rejected before they run. The response is a standard GraphQL response rather than the usual envelope.
+// @Tags        GraphQL
+// @Router      /graphql [post]
+// @Accept      json
+// @Produce     json
+// @Param       request  body     graph.Params      true  "GraphQL request"
+// @Param       X-Actor  header   string            false "who makes the change, recorded in the history"
+// @Success     200      {object} graphql.Response  "Executed operation, with errors if it failed"
+// @Response    400      {object} graphql.Response  "Malformed or invalid operation"
+func (h *handler) GraphQL(ctx *gin.Context) {
+	var params graph.Params
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while reading the GraphQL request",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	result := h.graphql.Exec(graph.WithActor(ctx.Request.Context(), requestActor(ctx)), params)
+
+	if result.Data == nil {
+		ctx.JSON(http.StatusBadRequest, result)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
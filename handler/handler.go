@@ -1,13 +1,156 @@
 package handler
 
-import "github.com/saidakhmatov/catalog_of_books/storage"
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/bus"
+	"github.com/saidakhmatov/catalog_of_books/config"
+	"github.com/saidakhmatov/catalog_of_books/graph"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+	"github.com/saidakhmatov/catalog_of_books/storage"
+)
 
 type handler struct {
-	strg storage.StorageI
+	strg    storage.StorageI
+	blobs   storage.BlobStorageI
+	ids     helper.IDGenerator
+	events  *bus.Bus
+	graphql *graph.Schema
+	cfg     config.Config
 }
 
-func NewHandler(strg storage.StorageI) *handler {
+func NewHandler(strg storage.StorageI, blobs storage.BlobStorageI, ids helper.IDGenerator, events *bus.Bus, schema *graph.Schema, cfg config.Config) *handler {
 	return &handler{
-		strg: strg,
+		strg:    strg,
+		blobs:   blobs,
+		ids:     ids,
+		events:  events,
+		graphql: schema,
+		cfg:     cfg,
+	}
+}
+
+// redirectPermanently answers with a 301 to the requested URL with its last path segment,
+// the id or slug the client asked for, replaced by target.
+func redirectPermanently(ctx *gin.Context, requested, target string) {
+	location := strings.TrimSuffix(ctx.Request.URL.Path, requested) + target
+	if ctx.Request.URL.RawQuery != "" {
+		location += "?" + ctx.Request.URL.RawQuery
+	}
+
+	ctx.Redirect(http.StatusMovedPermanently, location)
+}
+
+// preferMinimal reports whether the client sent Prefer: return=minimal to get only the id
+// of a created resource or the affected row count of an update, and acknowledges the
+// preference with Preference-Applied when it did.
+func preferMinimal(ctx *gin.Context) bool {
+	for _, header := range ctx.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.ReplaceAll(strings.TrimSpace(preference), " ", ""), "return=minimal") {
+				ctx.Header("Preference-Applied", "return=minimal")
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseAsOf reads the as_of query parameter, an RFC 3339 timestamp or a date, which is
+// taken as the end of that day in UTC. Without the parameter it returns the zero time.
+func parseAsOf(ctx *gin.Context) (time.Time, error) {
+	raw, ok := ctx.GetQuery("as_of")
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	if asOf, err := time.Parse(time.RFC3339, raw); err == nil {
+		return asOf, nil
+	}
+
+	day, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, errors.New("as_of must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+
+	return day.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// requestActor names who makes a change for the audit log. Until requests are
+// authenticated it is taken from the X-Actor header, and is "anonymous" without one.
+func requestActor(ctx *gin.Context) string {
+	if actor := strings.TrimSpace(ctx.GetHeader("X-Actor")); len(actor) > 0 {
+		return actor
+	}
+	return "anonymous"
+}
+
+// resourceLocation is the URL of the resource with the given id in the collection the
+// request was posted to.
+func resourceLocation(ctx *gin.Context, id string) string {
+	return strings.TrimSuffix(ctx.Request.URL.Path, "/") + "/" + id
+}
+
+// missingIDs returns the requested ids that are not among the found ones, in request order.
+func missingIDs(requested, found []string) []string {
+	present := make(map[string]bool, len(found))
+	for _, id := range found {
+		present[id] = true
+	}
+
+	resp := []string{}
+	for _, id := range requested {
+		if !present[id] {
+			resp = append(resp, id)
+		}
+	}
+	return resp
+}
+
+// slugRoutes are the routes whose :id may also be a slug on GET.
+var slugRoutes = []string{"/books/:id", "/authors/:id", "/book_category/:id"}
+
+// ValidateIDParams answers 400 for requests whose id path parameters, :id and every
+// :something_id, are not well-formed ids, so malformed input never reaches the database.
+func (h *handler) ValidateIDParams(ctx *gin.Context) {
+	for _, param := range ctx.Params {
+		if param.Key != "id" && !strings.HasSuffix(param.Key, "_id") {
+			continue
+		}
+
+		if helper.IsID(param.Value) {
+			continue
+		}
+
+		if param.Key == "id" && ctx.Request.Method == http.MethodGet && isSlugRoute(ctx.FullPath()) && helper.IsSlug(param.Value) {
+			continue
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   "invalid " + param.Key + " " + strconv.Quote(param.Value) + ", must be a UUID",
+				Message: "Bad Request",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.Next()
+}
+
+func isSlugRoute(fullPath string) bool {
+	for _, route := range slugRoutes {
+		if strings.HasSuffix(fullPath, route) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary  Get the change history of a book
+// @ID       get_book_history_id
+// @Tags     Book
+// @Router   /books/{id}/history [get]
+// @Produce  json
+// @Param    id     path     string          true  "Book ID"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Changes, newest first"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetBookHistory(ctx *gin.Context) {
+	h.getHistory(ctx, "book")
+}
+
+// @Summary  Get the change history of an author
+// @ID       get_author_history_id
+// @Tags     Author
+// @Router   /authors/{id}/history [get]
+// @Produce  json
+// @Param    id     path     string          true  "Author ID"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Changes, newest first"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetAuthorHistory(ctx *gin.Context) {
+	h.getHistory(ctx, "author")
+}
+
+// @Summary  Get the change history of a book category
+// @ID       get_book_category_history_id
+// @Tags     BookCategory
+// @Router   /book_category/{id}/history [get]
+// @Produce  json
+// @Param    id     path     string          true  "Book Category ID"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Changes, newest first"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetBookCategoryHistory(ctx *gin.Context) {
+	h.getHistory(ctx, "book_category")
+}
+
+// getHistory answers with a page of the audit entries of the entity with the :id path
+// parameter. Deleted entities keep their history.
+func (h *handler) getHistory(ctx *gin.Context, entity string) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting history",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting history",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	res, err := h.strg.AuditRepo().GetHistory(entity, ctx.Param("id"), qP)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting history",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Place a hold on a book
+// @ID          create_hold_id
+// @Description joins the FIFO queue for the next returned copy
+// @Tags        Hold
+// @Router      /books/{id}/holds [POST]
+// @Accept      json
+// @Param       id   path string            true "Book ID"
+// @Param       hold body models.CreateHold true "Hold Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateHold(ctx *gin.Context) {
+	var holdCreate models.CreateHold
+	var hold models.Hold
+
+	if err := ctx.ShouldBindJSON(&holdCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	dt := time.Now()
+
+	hold.ID = h.ids.NewID()
+	hold.BookID = ctx.Param("id")
+	hold.MemberID = holdCreate.MemberID
+	hold.Status = models.HoldStatusWaiting
+	hold.CreatedAt = dt
+	hold.UpdatedAt = dt
+
+	res, err := h.strg.HoldRepo().CreateHold(hold)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while placing hold",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully Created",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get the hold queue of a book
+// @ID       get_book_holds_id
+// @Tags     Hold
+// @Router   /books/{id}/holds [get]
+// @Produce  json
+// @Param    id  path     string          true "Book ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetBookHolds(ctx *gin.Context) {
+	res, err := h.strg.HoldRepo().GetBookHolds(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting book holds",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get the holds of a member
+// @ID       get_member_holds_id
+// @Tags     Hold
+// @Router   /members/{id}/holds [get]
+// @Produce  json
+// @Param    id  path     string          true "Member ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetMemberHolds(ctx *gin.Context) {
+	res, err := h.strg.HoldRepo().GetMemberHolds(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting member holds",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get hold by ID
+// @ID       get_hold_id
+// @Tags     Hold
+// @Router   /holds/{id} [get]
+// @Produce  json
+// @Param    id  path     string          true "Hold ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetHold(ctx *gin.Context) {
+	res, err := h.strg.HoldRepo().GetHold(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting hold",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Cancel a hold
+// @ID       cancel_hold_id
+// @Tags     Hold
+// @Router   /holds/{id}/cancel [post]
+// @Produce  json
+// @Param    id  path     string          true "Hold ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) CancelHold(ctx *gin.Context) {
+	res, err := h.strg.HoldRepo().CancelHold(ctx.Param("id"), h.cfg.HoldPickupPeriod())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while cancelling hold",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/fines"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Check a copy out to a member
+// @ID          create_loan_id
+// @Description due date depends on the format of the borrowed book
+// @Tags        Loan
+// @Router      /loans [POST]
+// @Accept      json
+// @Param       loan body models.CreateLoan true "Loan Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateLoan(ctx *gin.Context) {
+	var loanCreate models.CreateLoan
+	var loan models.Loan
+
+	if err := ctx.ShouldBindJSON(&loanCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	bookCopy, err := h.strg.CopyRepo().GetCopyByBarcode(loanCreate.Barcode)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while looking up copy",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	book, err := h.strg.BookRepo().GetBook(bookCopy.BookID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while looking up book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	dt := time.Now()
+
+	loan.ID = h.ids.NewID()
+	loan.CopyID = bookCopy.ID
+	loan.MemberID = loanCreate.MemberID
+	loan.CheckedOutAt = dt
+	loan.DueAt = dt.Add(h.cfg.LoanPeriod(book.Format))
+	loan.CreatedAt = dt
+	loan.UpdatedAt = dt
+
+	res, err := h.strg.LoanRepo().CreateLoan(loan, h.cfg.FineRules.MaxBalance)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while checking out",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully checked out",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary     Return a borrowed copy
+// @ID          return_loan_id
+// @Description charges the overdue fine to the member's ledger
+// @Tags        Loan
+// @Router      /loans/{id}/return [post]
+// @Produce     json
+// @Param       id  path     string          true "Loan ID"
+// @Success     200 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) ReturnLoan(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	loan, err := h.strg.LoanRepo().GetLoan(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting loan",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	book, err := h.strg.BookRepo().GetBook(loan.BookID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while looking up book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	returnedAt := time.Now()
+	fine := fines.Accrued(h.cfg.FineRules, book.Format, loan.DueAt, returnedAt)
+
+	res, err := h.strg.LoanRepo().ReturnLoan(id, returnedAt, fine, h.cfg.HoldPickupPeriod())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while returning loan",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully returned",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get all loans
+// @ID       get_all_loans_id
+// @Router   /loans [GET]
+// @Tags     Loan
+// @Produce  json
+// @Param    member_id query    string          false "Member ID"
+// @Param    active    query    bool            false "only loans that are still out"
+// @Param    limit     query    string          false "limit"
+// @Param    offset    query    string          false "offset"
+// @Success  200       {object} models.Response "Success Response"
+// @Response 400       {object} models.Response "Bad Request Error"
+func (h *handler) GetAllLoans(ctx *gin.Context) {
+	var qP models.LoanQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all loans",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all loans",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	memberID, member_id_exists := ctx.GetQuery("member_id")
+
+	if member_id_exists {
+		qP.MemberID = memberID
+	}
+
+	active, active_exists := ctx.GetQuery("active")
+
+	if active_exists {
+		res_active, err := strconv.ParseBool(active)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all loans",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.ActiveOnly = res_active
+	}
+
+	res, err := h.strg.LoanRepo().GetAllLoans(qP)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all loans",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get loan by ID
+// @ID       get_loan_id
+// @Tags     Loan
+// @Router   /loans/{id} [get]
+// @Produce  json
+// @Param    id  path     string          true "Loan ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetLoan(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.strg.LoanRepo().GetLoan(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting loan",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary     Get the fine accrued on a loan
+// @ID          get_loan_fine_id
+// @Description for open loans the amount grows until the copy is returned
+// @Tags        Loan
+// @Router      /loans/{id}/fine [get]
+// @Produce     json
+// @Param       id  path     string          true "Loan ID"
+// @Success     200 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) GetLoanFine(ctx *gin.Context) {
+	loan, err := h.strg.LoanRepo().GetLoan(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting loan",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	book, err := h.strg.BookRepo().GetBook(loan.BookID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while looking up book",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	end := time.Now()
+	if loan.ReturnedAt != nil {
+		end = *loan.ReturnedAt
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data: models.AccruedFine{
+				LoanID:      loan.ID,
+				OverdueDays: fines.OverdueDays(h.cfg.FineRules, loan.DueAt, end),
+				Amount:      fines.Accrued(h.cfg.FineRules, book.Format, loan.DueAt, end),
+				Final:       loan.ReturnedAt != nil,
+			},
+		},
+	})
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Register a library member
+// @ID          create_member_id
+// @Description max_loans defaults to the configured borrowing limit
+// @Tags        Member
+// @Router      /members [POST]
+// @Accept      json
+// @Param       member body models.CreateMember true "Member Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateMember(ctx *gin.Context) {
+	var memberCreate models.CreateMember
+	var member models.Member
+
+	if err := ctx.ShouldBindJSON(&memberCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	dt := time.Now()
+
+	member.ID = h.ids.NewID()
+	member.Firstname = memberCreate.Firstname
+	member.Lastname = memberCreate.Lastname
+	member.Email = memberCreate.Email
+	member.Phone = memberCreate.Phone
+	member.MaxLoans = memberCreate.MaxLoans
+	member.CreatedAt = dt
+	member.UpdatedAt = dt
+
+	if member.MaxLoans == 0 {
+		member.MaxLoans = h.cfg.DefaultMaxLoans
+	}
+
+	res, err := h.strg.MemberRepo().CreateMember(member)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully Created",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get all members
+// @ID       get_all_members_id
+// @Router   /members [GET]
+// @Tags     Member
+// @Produce  json
+// @Param    search query    string          false "name or email"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Success Response"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetAllMembers(ctx *gin.Context) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all members",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all members",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	search, search_exists := ctx.GetQuery("search")
+
+	if search_exists {
+		qP.Search = search
+	}
+
+	res, err := h.strg.MemberRepo().GetAllMembers(qP)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all members",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get member by ID
+// @ID       get_member_id
+// @Tags     Member
+// @Router   /members/{id} [get]
+// @Produce  json
+// @Param    id  path     string          true "Member ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetMember(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.strg.MemberRepo().GetMember(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting member",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Update member
+// @Tags     Member
+// @ID       update_member_id
+// @Router   /members/{id} [put]
+// @Accept   json
+// @Produce  json
+// @Param    id     path     string              true "Member ID"
+// @Param    member body     models.UpdateMember true "Update Model"
+// @Success  200    {object} models.Response     "Success Response"
+// @Response 400    {object} models.Response     "Bad Request Error"
+func (h *handler) UpdateMember(ctx *gin.Context) {
+	var memberModel models.UpdateMember
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&memberModel); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.MemberRepo().UpdateMember(memberModel, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  delete a member by id
+// @Tags     Member
+// @Router   /members/{id} [delete]
+// @ID       delete_member_id
+// @Param    id  path     string          true "Member ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) DeleteMember(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.strg.MemberRepo().DeleteMember(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while deleting member",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Review a book
+// @ID          create_review_id
+// @Description one review per reviewer per book
+// @Tags        Review
+// @Router      /books/{id}/reviews [POST]
+// @Accept      json
+// @Param       id     path string              true "Book ID"
+// @Param       review body models.CreateReview true "Review Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateReview(ctx *gin.Context) {
+	var reviewCreate models.CreateReview
+	var review models.Review
+
+	if err := ctx.ShouldBindJSON(&reviewCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	dt := time.Now()
+
+	review.ID = h.ids.NewID()
+	review.BookID = ctx.Param("id")
+	review.ReviewerID = reviewCreate.ReviewerID
+	review.Rating = reviewCreate.Rating
+	review.Text = reviewCreate.Text
+	review.CreatedAt = dt
+	review.UpdatedAt = dt
+
+	res, err := h.strg.ReviewRepo().CreateReview(review, requestActor(ctx))
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully Created",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get all reviews of a book
+// @ID       get_all_reviews_id
+// @Router   /books/{id}/reviews [GET]
+// @Tags     Review
+// @Produce  json
+// @Param    id     path     string          true  "Book ID"
+// @Param    search query    string          false "Search Query"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Success Response"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetAllReviews(ctx *gin.Context) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all reviews",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all reviews",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	search, search_exists := ctx.GetQuery("search")
+
+	if search_exists {
+		qP.Search = search
+	}
+
+	res, err := h.strg.ReviewRepo().GetAllReviews(ctx.Param("id"), qP)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all reviews",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get a review of a book by ID
+// @ID       get_review_id
+// @Tags     Review
+// @Router   /books/{id}/reviews/{review_id} [get]
+// @Produce  json
+// @Param    id        path     string          true "Book ID"
+// @Param    review_id path     string          true "Review ID"
+// @Success  200       {object} models.Response "Success Response"
+// @Response 400       {object} models.Response "Bad Request Error"
+func (h *handler) GetReview(ctx *gin.Context) {
+	res, err := h.strg.ReviewRepo().GetReview(ctx.Param("id"), ctx.Param("review_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting review",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Update a review of a book
+// @Tags     Review
+// @ID       update_review_id
+// @Router   /books/{id}/reviews/{review_id} [put]
+// @Accept   json
+// @Produce  json
+// @Param    id        path     string              true "Book ID"
+// @Param    review_id path     string              true "Review ID"
+// @Param    review    body     models.UpdateReview true "Update Model"
+// @Success  200       {object} models.Response     "Success Response"
+// @Response 400       {object} models.Response     "Bad Request Error"
+func (h *handler) UpdateReview(ctx *gin.Context) {
+	var reviewModel models.UpdateReview
+
+	if err := ctx.ShouldBindJSON(&reviewModel); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.ReviewRepo().UpdateReview(reviewModel, ctx.Param("id"), ctx.Param("review_id"), requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  delete a review of a book
+// @Tags     Review
+// @Router   /books/{id}/reviews/{review_id} [delete]
+// @ID       delete_review_id
+// @Param    id        path     string          true "Book ID"
+// @Param    review_id path     string          true "Review ID"
+// @Success  200       {object} models.Response "Success Response"
+// @Response 400       {object} models.Response "Bad Request Error"
+func (h *handler) DeleteReview(ctx *gin.Context) {
+	res, err := h.strg.ReviewRepo().DeleteReview(ctx.Param("id"), ctx.Param("review_id"), requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while deleting review",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
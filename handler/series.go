@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// @Summary     Create a series
+// @ID          create_series_id
+// @Description groups books in reading order
+// @Tags        Series
+// @Router      /series [POST]
+// @Accept      json
+// @Param       series body models.CreateSeries true "Series Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Success Response"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateSeries(ctx *gin.Context) {
+	var seriesCreate models.CreateSeries
+	var series models.Series
+
+	if err := ctx.ShouldBindJSON(&seriesCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	dt := time.Now()
+
+	series.ID = h.ids.NewID()
+	series.SeriesName = seriesCreate.SeriesName
+	series.Description = seriesCreate.Description
+	series.CreatedAt = dt
+	series.UpdatedAt = dt
+
+	res, err := h.strg.SeriesRepo().CreateSeries(series)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully Created",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get all series
+// @ID       get_all_series_id
+// @Router   /series [GET]
+// @Tags     Series
+// @Produce  json
+// @Param    search query    string          false "Search Query"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Success Response"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetAllSeries(ctx *gin.Context) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all series",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all series",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	search, search_exists := ctx.GetQuery("search")
+
+	if search_exists {
+		qP.Search = search
+	}
+
+	res, err := h.strg.SeriesRepo().GetAllSeries(qP)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all series",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get series by ID
+// @ID       get_series_id
+// @Tags     Series
+// @Router   /series/{id} [get]
+// @Produce  json
+// @Param    id  path     string          true "Series ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetSeries(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.strg.SeriesRepo().GetSeries(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting series",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Update series
+// @Tags     Series
+// @ID       update_series_id
+// @Router   /series/{id} [put]
+// @Accept   json
+// @Produce  json
+// @Param    id     path     string              true "Series ID"
+// @Param    series body     models.UpdateSeries true "Update Model"
+// @Success  200    {object} models.Response     "Success Response"
+// @Response 400    {object} models.Response     "Bad Request Error"
+func (h *handler) UpdateSeries(ctx *gin.Context) {
+	var seriesModel models.UpdateSeries
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&seriesModel); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.SeriesRepo().UpdateSeries(seriesModel, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  delete a series by id
+// @Tags     Series
+// @Router   /series/{id} [delete]
+// @ID       delete_series_id
+// @Param    id  path     string          true "Series ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) DeleteSeries(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.strg.SeriesRepo().DeleteSeries(id, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while deleting series",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get the books of a series in reading order
+// @ID       get_series_books_id
+// @Tags     Series
+// @Router   /series/{id}/books [get]
+// @Produce  json
+// @Param    id  path     string          true "Series ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) GetSeriesBooks(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.strg.SeriesRepo().GetSeriesBooks(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting series books",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Put a book into a series at the given position
+// @ID       add_book_to_series_id
+// @Tags     Series
+// @Router   /series/{id}/books/{book_id} [put]
+// @Accept   json
+// @Produce  json
+// @Param    id         path     string                  true "Series ID"
+// @Param    book_id    path     string                  true "Book ID"
+// @Param    membership body     models.SeriesMembership true "Reading order position"
+// @Success  200        {object} models.Response         "Success Response"
+// @Response 400        {object} models.Response         "Bad Request Error"
+func (h *handler) AddBookToSeries(ctx *gin.Context) {
+	var membership models.SeriesMembership
+	id := ctx.Param("id")
+	bookID := ctx.Param("book_id")
+
+	if err := ctx.ShouldBindJSON(&membership); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.SeriesRepo().AddBookToSeries(id, bookID, membership.Position, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while adding book to series",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Remove a book from a series
+// @ID       remove_book_from_series_id
+// @Tags     Series
+// @Router   /series/{id}/books/{book_id} [delete]
+// @Param    id      path     string          true "Series ID"
+// @Param    book_id path     string          true "Book ID"
+// @Success  200     {object} models.Response "Success Response"
+// @Response 400     {object} models.Response "Bad Request Error"
+func (h *handler) RemoveBookFromSeries(ctx *gin.Context) {
+	id := ctx.Param("id")
+	bookID := ctx.Param("book_id")
+
+	res, err := h.strg.SeriesRepo().RemoveBookFromSeries(id, bookID, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while removing book from series",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
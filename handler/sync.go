@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// syncPageSize is the default and largest number of entities in a sync page. Each entity
+// kind of a page is read in one batch, so it is bounded by the batch read limit.
+const syncPageSize = helper.MaxBatchIDs
+
+// syncBookFields are the fields of a book in a sync page. Availability follows the copies
+// of the book, whose changes are not in the change log, so it is left out rather than
+// synced stale.
+var syncBookFields = []string{
+	"id",
+	"slug",
+	"book_name",
+	"author_id",
+	"category_id",
+	"publication_year",
+	"language",
+	"page_count",
+	"description",
+	"edition",
+	"format",
+	"series_id",
+	"series_position",
+	"average_rating",
+	"rating_count",
+	"created_at",
+	"updated_at",
+}
+
+// @Summary     Sync catalog changes
+// @ID          sync_id
+// @Description Books, authors and categories created, updated or deleted since a sync token, oldest change first. Every changed entity appears once with its current state, or as a tombstone if it was deleted. Without a token the whole catalog is returned. Pass next_token to the next sync; has_more tells that another page is ready right away.
+// @Tags        Sync
+// @Router      /sync [get]
+// @Produce     json
+// @Param       since query    string          false "sync token from a previous sync"
+// @Param       limit query    string          false "entities per page, at most 100"
+// @Success     200   {object} models.Response "Page of changes"
+// @Response    400   {object} models.Response "Bad Request Error"
+// @Failure     500   {object} models.Response "Internal Server Error"
+func (h *handler) Sync(ctx *gin.Context) {
+	since, err := parseSyncToken(ctx.Query("since"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while syncing",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	limit := syncPageSize
+	if raw, ok := ctx.GetQuery("limit"); ok {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > syncPageSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   fmt.Sprintf("limit must be between 1 and %d", syncPageSize),
+					Message: "Error while syncing",
+					Data:    nil,
+				},
+			})
+			return
+		}
+	}
+
+	changed, err := h.strg.EventRepo().GetChangedEntities(since, limit+1)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while syncing",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	page := models.SyncPage{
+		Changes:   []models.SyncChange{},
+		NextToken: syncToken(since),
+		HasMore:   len(changed) > limit,
+	}
+	if page.HasMore {
+		changed = changed[:limit]
+	}
+
+	current, err := h.currentEntities(changed)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while syncing",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	for _, change := range changed {
+		data, ok := current[change.Entity][change.EntityID]
+		page.Changes = append(page.Changes, models.SyncChange{
+			Entity:  change.Entity,
+			ID:      change.EntityID,
+			Deleted: !ok,
+			Data:    data,
+		})
+		page.NextToken = syncToken(change.Seq)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Changes since the sync token",
+			Data:    page,
+		},
+	})
+}
+
+// currentEntities reads the changed entities as they are now, keyed by entity and id.
+// Deleted entities are missing. An entity changed again since the change log was read
+// is returned in its newer state and comes up again in the next sync.
+func (h *handler) currentEntities(changed []models.EntityChange) (map[string]map[string]interface{}, error) {
+	ids := make(map[string][]string)
+	for _, change := range changed {
+		ids[change.Entity] = append(ids[change.Entity], change.EntityID)
+	}
+
+	resp := make(map[string]map[string]interface{})
+	for _, entity := range models.EventEntities {
+		resp[entity] = make(map[string]interface{})
+	}
+
+	if len(ids["book"]) > 0 {
+		books, err := h.strg.BookRepo().GetBooksByIDs(ids["book"], syncBookFields...)
+		if err != nil {
+			return resp, err
+		}
+		for _, book := range books {
+			resp["book"][book.ID] = book
+		}
+	}
+
+	if len(ids["author"]) > 0 {
+		authors, err := h.strg.AuthorRepo().GetAuthorsByIDs(ids["author"])
+		if err != nil {
+			return resp, err
+		}
+		for _, author := range authors {
+			resp["author"][author.ID] = author
+		}
+	}
+
+	if len(ids["book_category"]) > 0 {
+		categories, err := h.strg.BookCategoryRepo().GetBookCategoriesByIDs(ids["book_category"])
+		if err != nil {
+			return resp, err
+		}
+		for _, category := range categories {
+			resp["book_category"][category.ID] = category
+		}
+	}
+
+	return resp, nil
+}
+
+// syncToken returns the opaque token of the change log position seq.
+func syncToken(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+// parseSyncToken returns the change log position of a sync token, or the start of the
+// log for an empty token.
+func parseSyncToken(token string) (int64, error) {
+	if len(token) == 0 {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		seq, err := strconv.ParseInt(string(raw), 10, 64)
+		if err == nil && seq >= 0 {
+			return seq, nil
+		}
+	}
+
+	return 0, fmt.Errorf("since is not a valid sync token: %q", token)
+}
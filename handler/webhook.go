@@ -0,0 +1,486 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+	"github.com/saidakhmatov/catalog_of_books/webhook"
+)
+
+// unknownEvent returns an error for the first of events that is not an event type.
+func unknownEvent(events []string) error {
+	for _, event := range events {
+		if !helper.Contains(models.EventTypes, event) {
+			return fmt.Errorf("unknown event %q, must be one of %v", event, models.EventTypes)
+		}
+	}
+	return nil
+}
+
+// @Summary     Create a webhook
+// @ID          create_webhook_id
+// @Description Subscribes a URL to catalog events. Deliveries are POSTed as JSON and signed in X-Catalog-Signature with HMAC-SHA256 over "<X-Catalog-Timestamp>.<body>". The secret is only returned here.
+// @Tags        Webhook
+// @Router      /webhooks [post]
+// @Accept      json
+// @Param       webhook body models.CreateWebhook true "Webhook Body"
+// @Produce     json
+// @Success     201 {object} models.Response "Created webhook with its secret"
+// @Response    400 {object} models.Response "Bad Request Error"
+func (h *handler) CreateWebhook(ctx *gin.Context) {
+	var webhookCreate models.CreateWebhook
+
+	if err := ctx.ShouldBindJSON(&webhookCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	if err := unknownEvent(webhookCreate.Events); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	secret := webhookCreate.Secret
+	if len(secret) == 0 {
+		var err error
+		if secret, err = webhook.NewSecret(); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"response": models.Response{
+					Error: err.Error(),
+					Data:  nil,
+				},
+			})
+			return
+		}
+	}
+
+	dt := time.Now()
+
+	hook := models.Webhook{
+		ID:        h.ids.NewID(),
+		URL:       webhookCreate.URL,
+		Secret:    secret,
+		Events:    webhookCreate.Events,
+		Active:    webhookCreate.Active == nil || *webhookCreate.Active,
+		CreatedAt: dt,
+		UpdatedAt: dt,
+	}
+
+	res, err := h.strg.WebhookRepo().CreateWebhook(hook)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.Header("Location", resourceLocation(ctx, res.ID))
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Successfully created",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get all webhooks
+// @ID       get_all_webhooks_id
+// @Router   /webhooks [get]
+// @Tags     Webhook
+// @Produce  json
+// @Param    search query    string          false "Search in the URL"
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Success Response"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetAllWebhooks(ctx *gin.Context) {
+	var qP models.ApplicationQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all webhooks",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting all webhooks",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	search, search_exists := ctx.GetQuery("search")
+	if search_exists {
+		qP.Search = search
+	}
+
+	res, err := h.strg.WebhookRepo().GetAllWebhooks(qP)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting all webhooks",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get webhook by ID
+// @ID       get_webhook_id
+// @Tags     Webhook
+// @Router   /webhooks/{id} [get]
+// @Produce  json
+// @Param    id  path     string          true "Webhook ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+// @Response 404 {object} models.Response "Not found"
+func (h *handler) GetWebhook(ctx *gin.Context) {
+	res, err := h.strg.WebhookRepo().GetWebhook(ctx.Param("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no webhook with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting webhook",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Update webhook
+// @Tags     Webhook
+// @ID       update_webhook_id
+// @Router   /webhooks/{id} [put]
+// @Accept   json
+// @Produce  json
+// @Param    id      path     string               true "Webhook ID"
+// @Param    webhook body     models.UpdateWebhook true "Update Model"
+// @Success  200     {object} models.Response      "Updated webhook"
+// @Response 400     {object} models.Response      "Bad Request Error"
+// @Response 404     {object} models.Response      "Not found"
+func (h *handler) UpdateWebhook(ctx *gin.Context) {
+	var webhookModel models.UpdateWebhook
+
+	if err := ctx.ShouldBindJSON(&webhookModel); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	if err := unknownEvent(webhookModel.Events); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	res, err := h.strg.WebhookRepo().UpdateWebhook(webhookModel, ctx.Param("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no webhook with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error: err.Error(),
+				Data:  nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Delete webhook by ID
+// @Tags     Webhook
+// @Router   /webhooks/{id} [delete]
+// @ID       delete_webhook_id
+// @Param    id  path     string          true "Webhook ID"
+// @Success  200 {object} models.Response "Success Response"
+// @Response 400 {object} models.Response "Bad Request Error"
+func (h *handler) DeleteWebhook(ctx *gin.Context) {
+	res, err := h.strg.WebhookRepo().DeleteWebhook(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while deleting webhook",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get the delivery log of a webhook
+// @ID       get_webhook_deliveries_id
+// @Router   /webhooks/{id}/deliveries [get]
+// @Tags     Webhook
+// @Produce  json
+// @Param    id     path     string          true  "Webhook ID"
+// @Param    status query    string          false "status" Enums(pending, delivered, dead)
+// @Param    limit  query    string          false "limit"
+// @Param    offset query    string          false "offset"
+// @Success  200    {object} models.Response "Deliveries, newest first"
+// @Response 400    {object} models.Response "Bad Request Error"
+func (h *handler) GetWebhookDeliveries(ctx *gin.Context) {
+	var qP models.WebhookDeliveryQueryParamModel
+
+	offset, offset_exists := ctx.GetQuery("offset")
+	if offset_exists {
+		res_offset, err := strconv.Atoi(offset)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting deliveries",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Offset = res_offset
+	}
+
+	limit, limit_exists := ctx.GetQuery("limit")
+	if limit_exists {
+		res_limit, err := strconv.Atoi(limit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   err.Error(),
+					Message: "Error while getting deliveries",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Limit = res_limit
+	}
+
+	status, status_exists := ctx.GetQuery("status")
+	if status_exists {
+		if !helper.Contains([]string{models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead}, status) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"response": models.Response{
+					Error:   "status must be one of pending, delivered, dead",
+					Message: "Error while getting deliveries",
+					Data:    nil,
+				},
+			})
+			return
+		}
+
+		qP.Status = status
+	}
+
+	res, err := h.strg.WebhookRepo().GetDeliveries(ctx.Param("id"), qP)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting deliveries",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Get a delivery of a webhook with its attempts
+// @ID       get_webhook_delivery_id
+// @Router   /webhooks/{id}/deliveries/{delivery_id} [get]
+// @Tags     Webhook
+// @Produce  json
+// @Param    id          path     string          true "Webhook ID"
+// @Param    delivery_id path     string          true "Delivery ID"
+// @Success  200         {object} models.Response "Success Response"
+// @Response 400         {object} models.Response "Bad Request Error"
+// @Response 404         {object} models.Response "Not found"
+func (h *handler) GetWebhookDelivery(ctx *gin.Context) {
+	res, err := h.strg.WebhookRepo().GetDelivery(ctx.Param("id"), ctx.Param("delivery_id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no delivery with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while getting delivery",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
+
+// @Summary  Retry a dead-lettered delivery
+// @ID       retry_webhook_delivery_id
+// @Router   /webhooks/{id}/deliveries/{delivery_id}/retry [post]
+// @Tags     Webhook
+// @Produce  json
+// @Param    id          path     string          true "Webhook ID"
+// @Param    delivery_id path     string          true "Delivery ID"
+// @Success  200         {object} models.Response "Delivery queued again"
+// @Response 400         {object} models.Response "Bad Request Error"
+// @Response 404         {object} models.Response "No dead-lettered delivery with the given id"
+func (h *handler) RetryWebhookDelivery(ctx *gin.Context) {
+	res, err := h.strg.WebhookRepo().RetryDelivery(ctx.Param("id"), ctx.Param("delivery_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"response": models.Response{
+				Error:   err.Error(),
+				Message: "Error while retrying delivery",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	if res == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"response": models.Response{
+				Error:   "there is no dead-lettered delivery with the given id",
+				Message: "Not found",
+				Data:    nil,
+			},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"response": models.Response{
+			Error:   "false",
+			Message: "Success",
+			Data:    res,
+		},
+	})
+}
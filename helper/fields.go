@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ParseFields splits a comma separated fields parameter such as "id,book_name", dropping
+// blanks. An empty parameter yields nil, meaning every field.
+func ParseFields(raw string) []string {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); len(field) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// PickFields re-encodes v, an object or a list of objects, keeping only the given JSON
+// keys of each object. With no fields v is returned unchanged.
+func PickFields(v interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep numbers as they were encoded rather than round-tripping them through float64.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	switch value := decoded.(type) {
+	case map[string]interface{}:
+		return pick(value, fields), nil
+	case []interface{}:
+		for i, item := range value {
+			if object, ok := item.(map[string]interface{}); ok {
+				value[i] = pick(object, fields)
+			}
+		}
+		return value, nil
+	default:
+		return decoded, nil
+	}
+}
+
+func pick(object map[string]interface{}, fields []string) map[string]interface{} {
+	resp := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := object[field]; ok {
+			resp[field] = value
+		}
+	}
+	return resp
+}
@@ -8,3 +8,13 @@ func UUIDMaker() string {
 	id := uuid.New()
 	return id.String()
 }
+
+// Contains reports whether value is one of list.
+func Contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package helper
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	IDStrategyUUIDv4 = "uuidv4"
+	IDStrategyUUIDv7 = "uuidv7"
+	IDStrategyULID   = "ulid"
+)
+
+var IDStrategies = []string{IDStrategyUUIDv4, IDStrategyUUIDv7, IDStrategyULID}
+
+// IDGenerator creates primary keys for new rows. Every strategy returns the canonical
+// 36 character UUID text form so the ids fit the native uuid columns.
+type IDGenerator interface {
+	NewID() string
+}
+
+// IDGeneratorFunc adapts a plain function to IDGenerator.
+type IDGeneratorFunc func() string
+
+func (f IDGeneratorFunc) NewID() string {
+	return f()
+}
+
+// NewIDGenerator returns the generator for one of IDStrategies.
+func NewIDGenerator(strategy string) (IDGenerator, error) {
+	switch strategy {
+	case IDStrategyUUIDv4:
+		return IDGeneratorFunc(UUIDMaker), nil
+	case IDStrategyUUIDv7:
+		return &timeOrderedGenerator{version7: true}, nil
+	case IDStrategyULID:
+		return &timeOrderedGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown id strategy %q, must be one of %v", strategy, IDStrategies)
+	}
+}
+
+// IsID reports whether s is a UUID in its canonical hyphenated form.
+func IsID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+// MaxBatchIDs caps how many ids a single batch read may ask for.
+const MaxBatchIDs = 100
+
+// ParseIDs splits a comma separated ids parameter such as "a,b,c" into distinct ids, in
+// the order they were first given. Every id must be well-formed; ids are returned in the
+// lower case Postgres writes them in, so they match the ids of the rows read for them.
+func ParseIDs(raw string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range ParseFields(raw) {
+		if !IsID(id) {
+			return nil, fmt.Errorf("%q is not a valid id", id)
+		}
+		id = strings.ToLower(id)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("ids must list at least one id")
+	}
+	if len(ids) > MaxBatchIDs {
+		return nil, fmt.Errorf("at most %d ids can be fetched at once", MaxBatchIDs)
+	}
+
+	return ids, nil
+}
+
+// timeOrderedGenerator creates ids whose first 48 bits are the Unix time in milliseconds,
+// so they sort by creation and new rows land at the end of the primary key index.
+// With version7 set the ids are RFC 9562 UUIDv7: a 12 bit counter follows the timestamp
+// and the version and variant bits are set. Otherwise they are ULIDs, 48 bits of time and
+// 80 random bits, written as a UUID. Ids created within the same millisecond increase
+// monotonically in both cases.
+type timeOrderedGenerator struct {
+	version7 bool
+
+	mu   sync.Mutex
+	last [16]byte
+	ms   uint64
+}
+
+func (g *timeOrderedGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+
+	if ms > g.ms || !g.increment() {
+		if ms <= g.ms {
+			// The counter ran out within this millisecond; borrow the next one.
+			ms = g.ms + 1
+		}
+		if _, err := rand.Read(g.last[6:]); err != nil {
+			panic(fmt.Sprintf("reading random bytes: %v", err))
+		}
+		if g.version7 {
+			// Leave the top of the counter clear so it has room to grow.
+			g.last[6] &= 0x07
+		}
+		g.ms = ms
+	}
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], g.ms)
+	copy(g.last[:6], ts[2:])
+
+	id := g.last
+	if g.version7 {
+		id[6] = 0x70 | id[6]&0x0f
+		id[8] = 0x80 | id[8]&0x3f
+	}
+
+	return uuid.UUID(id).String()
+}
+
+// increment adds one to the part after the timestamp: the 12 bit counter of a UUIDv7 or
+// the 80 random bits of a ULID. It reports false on overflow.
+func (g *timeOrderedGenerator) increment() bool {
+	if g.version7 {
+		counter := binary.BigEndian.Uint16(g.last[6:8]) & 0x0fff
+		if counter == 0x0fff {
+			return false
+		}
+		binary.BigEndian.PutUint16(g.last[6:8], counter+1)
+		return true
+	}
+
+	for i := 15; i >= 6; i-- {
+		g.last[i]++
+		if g.last[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength caps generated slugs; longer titles are cut at a word boundary.
+const MaxSlugLength = 80
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// cyrillic transliterates Russian and Ukrainian Cyrillic letters to Latin.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// uzbekCyrillic overrides cyrillic with the official Uzbek Latin spelling. It is used
+// for text containing one of the letters only Uzbek has.
+var uzbekCyrillic = map[rune]string{
+	'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h", 'х': "x", 'ж': "j", 'щ': "sh", 'ц': "ts",
+}
+
+// latinSpecial covers Latin letters that do not decompose into a base letter and a mark.
+var latinSpecial = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// Slugify turns a title or name into a lower-case, hyphen separated ASCII slug such as
+// "the-hobbit". Cyrillic is transliterated, and the Uzbek Latin apostrophes in oʻ and gʻ
+// are dropped. The result is empty when s has nothing that can be transliterated.
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+
+	uzbek := strings.ContainsAny(s, "ўқғҳ")
+
+	var b strings.Builder
+	for _, r := range s {
+		if uzbek {
+			if latin, ok := uzbekCyrillic[r]; ok {
+				b.WriteString(latin)
+				continue
+			}
+		}
+		if latin, ok := cyrillic[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		if latin, ok := latinSpecial[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		switch r {
+		case 'ʻ', 'ʼ', '\'', '’', '‘', '`':
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, b.String())
+	if err != nil {
+		folded = b.String()
+	}
+
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	slug := ""
+	for _, word := range words {
+		if len(slug)+len(word)+1 > MaxSlugLength && len(slug) > 0 {
+			break
+		}
+		if len(slug) > 0 {
+			slug += "-"
+		}
+		slug += word
+	}
+
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+	}
+
+	return slug
+}
+
+// IsSlug reports whether s has the shape of a slug produced by Slugify.
+func IsSlug(s string) bool {
+	return slugPattern.MatchString(s)
+}
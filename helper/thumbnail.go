@@ -0,0 +1,60 @@
+package helper
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail scales img down to width pixels, keeping its aspect ratio, by averaging the
+// source pixels covered by each destination pixel. Images that are already narrow enough
+// are returned unchanged.
+func Thumbnail(img image.Image, width int) image.Image {
+	src := img.Bounds()
+	if width <= 0 || src.Dx() <= width {
+		return img
+	}
+
+	height := src.Dy() * width / src.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		sy0 := src.Min.Y + y*src.Dy()/height
+		sy1 := src.Min.Y + (y+1)*src.Dy()/height
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			sx0 := src.Min.X + x*src.Dx()/width
+			sx1 := src.Min.X + (x+1)*src.Dx()/width
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
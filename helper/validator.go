@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// MinPublicationYear is the earliest publication year the catalog accepts.
+const MinPublicationYear = 1400
+
+// IsPublicationYear reports whether year lies between MinPublicationYear and next year.
+func IsPublicationYear(year int) bool {
+	return year >= MinPublicationYear && year <= time.Now().Year()+1
+}
+
+// IsWebhookURL reports whether raw is an absolute http or https URL with a host, the only
+// URLs deliveries are posted to.
+func IsWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && len(u.Hostname()) > 0
+}
+
+// RegisterValidators adds the catalog specific binding tags to gin's validator.
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+
+	if err := v.RegisterValidation("iso639_1", func(fl validator.FieldLevel) bool {
+		return IsLanguageCode(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+
+	if err := v.RegisterValidation("publication_year", func(fl validator.FieldLevel) bool {
+		return IsPublicationYear(int(fl.Field().Int()))
+	}); err != nil {
+		return err
+	}
+
+	return v.RegisterValidation("webhook_url", func(fl validator.FieldLevel) bool {
+		return IsWebhookURL(fl.Field().String())
+	})
+}
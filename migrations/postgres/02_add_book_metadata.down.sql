@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS "idx_book_publication_year";
+DROP INDEX IF EXISTS "idx_book_format";
+DROP INDEX IF EXISTS "idx_book_language";
+
+ALTER TABLE "book" DROP CONSTRAINT IF EXISTS "chk_book_format";
+ALTER TABLE "book" DROP CONSTRAINT IF EXISTS "chk_book_page_count";
+ALTER TABLE "book" DROP CONSTRAINT IF EXISTS "chk_book_publication_year";
+
+ALTER TABLE "book"
+  DROP COLUMN IF EXISTS "format",
+  DROP COLUMN IF EXISTS "edition",
+  DROP COLUMN IF EXISTS "description",
+  DROP COLUMN IF EXISTS "page_count",
+  DROP COLUMN IF EXISTS "language",
+  DROP COLUMN IF EXISTS "publication_year";
@@ -0,0 +1,19 @@
+ALTER TABLE "book"
+  ADD COLUMN "publication_year" int,
+  ADD COLUMN "language" varchar NOT NULL DEFAULT '',
+  ADD COLUMN "page_count" int,
+  ADD COLUMN "description" text NOT NULL DEFAULT '',
+  ADD COLUMN "edition" varchar NOT NULL DEFAULT '',
+  ADD COLUMN "format" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "book" ADD CONSTRAINT "chk_book_publication_year" CHECK ("publication_year" IS NULL OR "publication_year" >= 1400);
+
+ALTER TABLE "book" ADD CONSTRAINT "chk_book_page_count" CHECK ("page_count" IS NULL OR "page_count" > 0);
+
+ALTER TABLE "book" ADD CONSTRAINT "chk_book_format" CHECK ("format" IN ('', 'hardcover', 'paperback', 'ebook', 'audiobook'));
+
+CREATE INDEX "idx_book_language" ON "book" ("language");
+
+CREATE INDEX "idx_book_format" ON "book" ("format");
+
+CREATE INDEX "idx_book_publication_year" ON "book" ("publication_year");
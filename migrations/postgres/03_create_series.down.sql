@@ -0,0 +1,9 @@
+ALTER TABLE "book" DROP CONSTRAINT IF EXISTS "uq_book_series_position";
+ALTER TABLE "book" DROP CONSTRAINT IF EXISTS "chk_book_series_position";
+ALTER TABLE "book" DROP CONSTRAINT IF EXISTS "fk_book_series";
+
+ALTER TABLE "book"
+  DROP COLUMN IF EXISTS "series_position",
+  DROP COLUMN IF EXISTS "series_id";
+
+DROP TABLE IF EXISTS "series";
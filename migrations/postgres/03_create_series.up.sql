@@ -0,0 +1,19 @@
+CREATE TABLE "series" (
+  "id" varchar PRIMARY KEY,
+  "series_name" varchar NOT NULL,
+  "description" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "book"
+  ADD COLUMN "series_id" varchar,
+  ADD COLUMN "series_position" numeric(8, 2);
+
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_series" FOREIGN KEY ("series_id") REFERENCES "series" ("id");
+
+ALTER TABLE "book" ADD CONSTRAINT "chk_book_series_position" CHECK (
+  ("series_id" IS NULL AND "series_position" IS NULL) OR ("series_id" IS NOT NULL AND "series_position" > 0)
+);
+
+ALTER TABLE "book" ADD CONSTRAINT "uq_book_series_position" UNIQUE ("series_id", "series_position");
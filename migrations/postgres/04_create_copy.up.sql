@@ -0,0 +1,21 @@
+CREATE TABLE "copy" (
+  "id" varchar PRIMARY KEY,
+  "book_id" varchar NOT NULL,
+  "barcode" varchar NOT NULL,
+  "condition" varchar NOT NULL DEFAULT 'good',
+  "shelf_location" varchar NOT NULL DEFAULT '',
+  "acquisition_date" date,
+  "status" varchar NOT NULL DEFAULT 'available',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "copy" ADD CONSTRAINT "fk_copy_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+
+ALTER TABLE "copy" ADD CONSTRAINT "uq_copy_barcode" UNIQUE ("barcode");
+
+ALTER TABLE "copy" ADD CONSTRAINT "chk_copy_condition" CHECK ("condition" IN ('new', 'good', 'fair', 'poor', 'damaged'));
+
+ALTER TABLE "copy" ADD CONSTRAINT "chk_copy_status" CHECK ("status" IN ('available', 'on_loan', 'lost', 'withdrawn'));
+
+CREATE INDEX "idx_copy_book_id" ON "copy" ("book_id");
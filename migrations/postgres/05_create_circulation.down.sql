@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "loan";
+DROP TABLE IF EXISTS "member";
@@ -0,0 +1,35 @@
+CREATE TABLE "member" (
+  "id" varchar PRIMARY KEY,
+  "firstname" varchar NOT NULL,
+  "lastname" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "phone" varchar NOT NULL DEFAULT '',
+  "max_loans" int NOT NULL DEFAULT 5,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "member" ADD CONSTRAINT "uq_member_email" UNIQUE ("email");
+
+ALTER TABLE "member" ADD CONSTRAINT "chk_member_max_loans" CHECK ("max_loans" > 0);
+
+
+CREATE TABLE "loan" (
+  "id" varchar PRIMARY KEY,
+  "copy_id" varchar NOT NULL,
+  "member_id" varchar NOT NULL,
+  "checked_out_at" timestamptz NOT NULL DEFAULT (now()),
+  "due_at" timestamptz NOT NULL,
+  "returned_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "loan" ADD CONSTRAINT "fk_loan_copy" FOREIGN KEY ("copy_id") REFERENCES "copy" ("id");
+
+ALTER TABLE "loan" ADD CONSTRAINT "fk_loan_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+
+-- a copy can be out on at most one loan at a time
+CREATE UNIQUE INDEX "uq_loan_active_copy" ON "loan" ("copy_id") WHERE "returned_at" IS NULL;
+
+CREATE INDEX "idx_loan_member_id" ON "loan" ("member_id");
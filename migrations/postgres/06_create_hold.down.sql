@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "hold";
+
+UPDATE "copy" SET "status" = 'available' WHERE "status" = 'on_hold';
+
+ALTER TABLE "copy" DROP CONSTRAINT "chk_copy_status";
+
+ALTER TABLE "copy" ADD CONSTRAINT "chk_copy_status" CHECK ("status" IN ('available', 'on_loan', 'lost', 'withdrawn'));
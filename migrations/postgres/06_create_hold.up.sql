@@ -0,0 +1,31 @@
+ALTER TABLE "copy" DROP CONSTRAINT "chk_copy_status";
+
+ALTER TABLE "copy" ADD CONSTRAINT "chk_copy_status" CHECK ("status" IN ('available', 'on_loan', 'on_hold', 'lost', 'withdrawn'));
+
+
+CREATE TABLE "hold" (
+  "id" varchar PRIMARY KEY,
+  "book_id" varchar NOT NULL,
+  "member_id" varchar NOT NULL,
+  "copy_id" varchar,
+  "status" varchar NOT NULL DEFAULT 'waiting',
+  "pickup_expires_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_copy" FOREIGN KEY ("copy_id") REFERENCES "copy" ("id");
+
+ALTER TABLE "hold" ADD CONSTRAINT "chk_hold_status" CHECK ("status" IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired'));
+
+-- a member queues at most once per title
+CREATE UNIQUE INDEX "uq_hold_active_member_book" ON "hold" ("member_id", "book_id") WHERE "status" IN ('waiting', 'ready');
+
+-- a copy is held for at most one member
+CREATE UNIQUE INDEX "uq_hold_ready_copy" ON "hold" ("copy_id") WHERE "status" = 'ready';
+
+CREATE INDEX "idx_hold_queue" ON "hold" ("book_id", "created_at", "id") WHERE "status" = 'waiting';
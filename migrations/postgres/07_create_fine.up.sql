@@ -0,0 +1,19 @@
+CREATE TABLE "fine" (
+  "id" varchar PRIMARY KEY,
+  "member_id" varchar NOT NULL,
+  "loan_id" varchar,
+  "kind" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "fine" ADD CONSTRAINT "fk_fine_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+
+ALTER TABLE "fine" ADD CONSTRAINT "fk_fine_loan" FOREIGN KEY ("loan_id") REFERENCES "loan" ("id");
+
+ALTER TABLE "fine" ADD CONSTRAINT "chk_fine_kind" CHECK ("kind" IN ('charge', 'payment', 'waiver'));
+
+ALTER TABLE "fine" ADD CONSTRAINT "chk_fine_amount" CHECK ("amount" > 0);
+
+CREATE INDEX "idx_fine_member_id" ON "fine" ("member_id", "created_at");
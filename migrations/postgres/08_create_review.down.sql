@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "idx_book_average_rating";
+
+ALTER TABLE "book"
+  DROP COLUMN IF EXISTS "rating_count",
+  DROP COLUMN IF EXISTS "average_rating";
+
+DROP TABLE IF EXISTS "review";
@@ -0,0 +1,24 @@
+CREATE TABLE "review" (
+  "id" varchar PRIMARY KEY,
+  "book_id" varchar NOT NULL,
+  "reviewer_id" varchar NOT NULL,
+  "rating" smallint NOT NULL,
+  "text" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "review" ADD CONSTRAINT "fk_review_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+
+ALTER TABLE "review" ADD CONSTRAINT "fk_review_reviewer" FOREIGN KEY ("reviewer_id") REFERENCES "member" ("id");
+
+ALTER TABLE "review" ADD CONSTRAINT "chk_review_rating" CHECK ("rating" BETWEEN 1 AND 5);
+
+ALTER TABLE "review" ADD CONSTRAINT "uq_review_book_reviewer" UNIQUE ("book_id", "reviewer_id");
+
+
+ALTER TABLE "book"
+  ADD COLUMN "average_rating" numeric(3, 2) NOT NULL DEFAULT 0,
+  ADD COLUMN "rating_count" int NOT NULL DEFAULT 0;
+
+CREATE INDEX "idx_book_average_rating" ON "book" ("average_rating");
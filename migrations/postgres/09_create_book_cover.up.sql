@@ -0,0 +1,11 @@
+CREATE TABLE "book_cover" (
+  "book_id" varchar PRIMARY KEY,
+  "content_type" varchar NOT NULL,
+  "etag" varchar NOT NULL,
+  "size" bigint NOT NULL,
+  "width" int NOT NULL,
+  "height" int NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "book_cover" ADD CONSTRAINT "fk_book_cover_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id") ON DELETE CASCADE;
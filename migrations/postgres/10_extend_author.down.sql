@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS "idx_author_aliases";
+DROP INDEX IF EXISTS "idx_author_sort_name";
+
+ALTER TABLE "author" DROP CONSTRAINT IF EXISTS "chk_author_lifespan";
+
+ALTER TABLE "author"
+  DROP COLUMN IF EXISTS "sort_name",
+  DROP COLUMN IF EXISTS "display_name",
+  DROP COLUMN IF EXISTS "aliases",
+  DROP COLUMN IF EXISTS "biography",
+  DROP COLUMN IF EXISTS "nationality",
+  DROP COLUMN IF EXISTS "death_date",
+  DROP COLUMN IF EXISTS "birth_date";
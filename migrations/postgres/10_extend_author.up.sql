@@ -0,0 +1,14 @@
+ALTER TABLE "author"
+  ADD COLUMN "birth_date" date,
+  ADD COLUMN "death_date" date,
+  ADD COLUMN "nationality" varchar NOT NULL DEFAULT '',
+  ADD COLUMN "biography" text NOT NULL DEFAULT '',
+  ADD COLUMN "aliases" text[] NOT NULL DEFAULT '{}',
+  ADD COLUMN "display_name" varchar GENERATED ALWAYS AS ("firstname" || ' ' || "lastname") STORED,
+  ADD COLUMN "sort_name" varchar GENERATED ALWAYS AS ("lastname" || ', ' || "firstname") STORED;
+
+ALTER TABLE "author" ADD CONSTRAINT "chk_author_lifespan" CHECK ("death_date" IS NULL OR "birth_date" IS NULL OR "death_date" >= "birth_date");
+
+CREATE INDEX "idx_author_sort_name" ON "author" ("sort_name");
+
+CREATE INDEX "idx_author_aliases" ON "author" USING GIN ("aliases");
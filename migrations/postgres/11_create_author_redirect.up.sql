@@ -0,0 +1,9 @@
+CREATE TABLE "author_redirect" (
+  "from_id" varchar PRIMARY KEY,
+  "to_id" varchar NOT NULL,
+  "merged_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "author_redirect" ADD CONSTRAINT "fk_author_redirect_author" FOREIGN KEY ("to_id") REFERENCES "author" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "idx_author_redirect_to_id" ON "author_redirect" ("to_id");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "slug_history";
+
+ALTER TABLE "book_category" DROP COLUMN IF EXISTS "slug";
+ALTER TABLE "author" DROP COLUMN IF EXISTS "slug";
+ALTER TABLE "book" DROP COLUMN IF EXISTS "slug";
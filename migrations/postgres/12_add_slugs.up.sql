@@ -0,0 +1,51 @@
+ALTER TABLE "book" ADD COLUMN "slug" varchar;
+ALTER TABLE "author" ADD COLUMN "slug" varchar;
+ALTER TABLE "book_category" ADD COLUMN "slug" varchar;
+
+-- Existing rows get an ASCII-only slug; the first row per slug keeps it bare, the others
+-- are told apart by the start of their id, and rows without any ASCII letters use the id.
+UPDATE "book" b SET "slug" = s."slug" FROM (
+  SELECT "id", CASE
+    WHEN "base" = '' THEN "id"
+    WHEN row_number() OVER (PARTITION BY "base" ORDER BY "created_at", "id") = 1 THEN "base"
+    ELSE "base" || '-' || left("id", 8)
+  END AS "slug"
+  FROM (SELECT "id", "created_at", trim(both '-' from regexp_replace(lower("book_name"), '[^a-z0-9]+', '-', 'g')) AS "base" FROM "book") t
+) s WHERE b."id" = s."id";
+
+UPDATE "author" a SET "slug" = s."slug" FROM (
+  SELECT "id", CASE
+    WHEN "base" = '' THEN "id"
+    WHEN row_number() OVER (PARTITION BY "base" ORDER BY "created_at", "id") = 1 THEN "base"
+    ELSE "base" || '-' || left("id", 8)
+  END AS "slug"
+  FROM (SELECT "id", "created_at", trim(both '-' from regexp_replace(lower("firstname" || ' ' || "lastname"), '[^a-z0-9]+', '-', 'g')) AS "base" FROM "author") t
+) s WHERE a."id" = s."id";
+
+UPDATE "book_category" c SET "slug" = s."slug" FROM (
+  SELECT "id", CASE
+    WHEN "base" = '' THEN "id"
+    WHEN row_number() OVER (PARTITION BY "base" ORDER BY "created_at", "id") = 1 THEN "base"
+    ELSE "base" || '-' || left("id", 8)
+  END AS "slug"
+  FROM (SELECT "id", "created_at", trim(both '-' from regexp_replace(lower("category_name"), '[^a-z0-9]+', '-', 'g')) AS "base" FROM "book_category") t
+) s WHERE c."id" = s."id";
+
+ALTER TABLE "book" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "author" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "book_category" ALTER COLUMN "slug" SET NOT NULL;
+
+ALTER TABLE "book" ADD CONSTRAINT "uq_book_slug" UNIQUE ("slug");
+ALTER TABLE "author" ADD CONSTRAINT "uq_author_slug" UNIQUE ("slug");
+ALTER TABLE "book_category" ADD CONSTRAINT "uq_book_category_slug" UNIQUE ("slug");
+
+-- Slugs an entity used before a rename, so old URLs can redirect to the current one.
+CREATE TABLE "slug_history" (
+  "entity" varchar NOT NULL,
+  "slug" varchar NOT NULL,
+  "entity_id" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("entity", "slug")
+);
+
+CREATE INDEX "idx_slug_history_entity_id" ON "slug_history" ("entity", "entity_id");
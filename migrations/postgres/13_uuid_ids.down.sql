@@ -0,0 +1,84 @@
+ALTER TABLE "book" DROP CONSTRAINT "fk_book_author";
+ALTER TABLE "book" DROP CONSTRAINT "fk_book_category";
+ALTER TABLE "book" DROP CONSTRAINT "fk_book_series";
+ALTER TABLE "copy" DROP CONSTRAINT "fk_copy_book";
+ALTER TABLE "loan" DROP CONSTRAINT "fk_loan_copy";
+ALTER TABLE "loan" DROP CONSTRAINT "fk_loan_member";
+ALTER TABLE "hold" DROP CONSTRAINT "fk_hold_book";
+ALTER TABLE "hold" DROP CONSTRAINT "fk_hold_member";
+ALTER TABLE "hold" DROP CONSTRAINT "fk_hold_copy";
+ALTER TABLE "fine" DROP CONSTRAINT "fk_fine_member";
+ALTER TABLE "fine" DROP CONSTRAINT "fk_fine_loan";
+ALTER TABLE "review" DROP CONSTRAINT "fk_review_book";
+ALTER TABLE "review" DROP CONSTRAINT "fk_review_reviewer";
+ALTER TABLE "book_cover" DROP CONSTRAINT "fk_book_cover_book";
+ALTER TABLE "author_redirect" DROP CONSTRAINT "fk_author_redirect_author";
+
+ALTER TABLE "author"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar;
+
+ALTER TABLE "book_category"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar;
+
+ALTER TABLE "series"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar;
+
+ALTER TABLE "book"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar,
+  ALTER COLUMN "author_id" TYPE varchar USING "author_id"::varchar,
+  ALTER COLUMN "category_id" TYPE varchar USING "category_id"::varchar,
+  ALTER COLUMN "series_id" TYPE varchar USING "series_id"::varchar;
+
+ALTER TABLE "copy"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar,
+  ALTER COLUMN "book_id" TYPE varchar USING "book_id"::varchar;
+
+ALTER TABLE "member"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar;
+
+ALTER TABLE "loan"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar,
+  ALTER COLUMN "copy_id" TYPE varchar USING "copy_id"::varchar,
+  ALTER COLUMN "member_id" TYPE varchar USING "member_id"::varchar;
+
+ALTER TABLE "hold"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar,
+  ALTER COLUMN "book_id" TYPE varchar USING "book_id"::varchar,
+  ALTER COLUMN "member_id" TYPE varchar USING "member_id"::varchar,
+  ALTER COLUMN "copy_id" TYPE varchar USING "copy_id"::varchar;
+
+ALTER TABLE "fine"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar,
+  ALTER COLUMN "member_id" TYPE varchar USING "member_id"::varchar,
+  ALTER COLUMN "loan_id" TYPE varchar USING "loan_id"::varchar;
+
+ALTER TABLE "review"
+  ALTER COLUMN "id" TYPE varchar USING "id"::varchar,
+  ALTER COLUMN "book_id" TYPE varchar USING "book_id"::varchar,
+  ALTER COLUMN "reviewer_id" TYPE varchar USING "reviewer_id"::varchar;
+
+ALTER TABLE "book_cover"
+  ALTER COLUMN "book_id" TYPE varchar USING "book_id"::varchar;
+
+ALTER TABLE "author_redirect"
+  ALTER COLUMN "from_id" TYPE varchar USING "from_id"::varchar,
+  ALTER COLUMN "to_id" TYPE varchar USING "to_id"::varchar;
+
+ALTER TABLE "slug_history"
+  ALTER COLUMN "entity_id" TYPE varchar USING "entity_id"::varchar;
+
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_author" FOREIGN KEY ("author_id") REFERENCES "author" ("id");
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_category" FOREIGN KEY ("category_id") REFERENCES "book_category" ("id");
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_series" FOREIGN KEY ("series_id") REFERENCES "series" ("id");
+ALTER TABLE "copy" ADD CONSTRAINT "fk_copy_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+ALTER TABLE "loan" ADD CONSTRAINT "fk_loan_copy" FOREIGN KEY ("copy_id") REFERENCES "copy" ("id");
+ALTER TABLE "loan" ADD CONSTRAINT "fk_loan_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_copy" FOREIGN KEY ("copy_id") REFERENCES "copy" ("id");
+ALTER TABLE "fine" ADD CONSTRAINT "fk_fine_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+ALTER TABLE "fine" ADD CONSTRAINT "fk_fine_loan" FOREIGN KEY ("loan_id") REFERENCES "loan" ("id");
+ALTER TABLE "review" ADD CONSTRAINT "fk_review_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+ALTER TABLE "review" ADD CONSTRAINT "fk_review_reviewer" FOREIGN KEY ("reviewer_id") REFERENCES "member" ("id");
+ALTER TABLE "book_cover" ADD CONSTRAINT "fk_book_cover_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id") ON DELETE CASCADE;
+ALTER TABLE "author_redirect" ADD CONSTRAINT "fk_author_redirect_author" FOREIGN KEY ("to_id") REFERENCES "author" ("id") ON DELETE CASCADE;
@@ -0,0 +1,85 @@
+-- Foreign keys are dropped while both sides change type and are restored afterwards.
+ALTER TABLE "book" DROP CONSTRAINT "fk_book_author";
+ALTER TABLE "book" DROP CONSTRAINT "fk_book_category";
+ALTER TABLE "book" DROP CONSTRAINT "fk_book_series";
+ALTER TABLE "copy" DROP CONSTRAINT "fk_copy_book";
+ALTER TABLE "loan" DROP CONSTRAINT "fk_loan_copy";
+ALTER TABLE "loan" DROP CONSTRAINT "fk_loan_member";
+ALTER TABLE "hold" DROP CONSTRAINT "fk_hold_book";
+ALTER TABLE "hold" DROP CONSTRAINT "fk_hold_member";
+ALTER TABLE "hold" DROP CONSTRAINT "fk_hold_copy";
+ALTER TABLE "fine" DROP CONSTRAINT "fk_fine_member";
+ALTER TABLE "fine" DROP CONSTRAINT "fk_fine_loan";
+ALTER TABLE "review" DROP CONSTRAINT "fk_review_book";
+ALTER TABLE "review" DROP CONSTRAINT "fk_review_reviewer";
+ALTER TABLE "book_cover" DROP CONSTRAINT "fk_book_cover_book";
+ALTER TABLE "author_redirect" DROP CONSTRAINT "fk_author_redirect_author";
+
+ALTER TABLE "author"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid;
+
+ALTER TABLE "book_category"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid;
+
+ALTER TABLE "series"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid;
+
+ALTER TABLE "book"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid,
+  ALTER COLUMN "author_id" TYPE uuid USING "author_id"::uuid,
+  ALTER COLUMN "category_id" TYPE uuid USING "category_id"::uuid,
+  ALTER COLUMN "series_id" TYPE uuid USING "series_id"::uuid;
+
+ALTER TABLE "copy"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid,
+  ALTER COLUMN "book_id" TYPE uuid USING "book_id"::uuid;
+
+ALTER TABLE "member"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid;
+
+ALTER TABLE "loan"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid,
+  ALTER COLUMN "copy_id" TYPE uuid USING "copy_id"::uuid,
+  ALTER COLUMN "member_id" TYPE uuid USING "member_id"::uuid;
+
+ALTER TABLE "hold"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid,
+  ALTER COLUMN "book_id" TYPE uuid USING "book_id"::uuid,
+  ALTER COLUMN "member_id" TYPE uuid USING "member_id"::uuid,
+  ALTER COLUMN "copy_id" TYPE uuid USING "copy_id"::uuid;
+
+ALTER TABLE "fine"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid,
+  ALTER COLUMN "member_id" TYPE uuid USING "member_id"::uuid,
+  ALTER COLUMN "loan_id" TYPE uuid USING "loan_id"::uuid;
+
+ALTER TABLE "review"
+  ALTER COLUMN "id" TYPE uuid USING "id"::uuid,
+  ALTER COLUMN "book_id" TYPE uuid USING "book_id"::uuid,
+  ALTER COLUMN "reviewer_id" TYPE uuid USING "reviewer_id"::uuid;
+
+ALTER TABLE "book_cover"
+  ALTER COLUMN "book_id" TYPE uuid USING "book_id"::uuid;
+
+ALTER TABLE "author_redirect"
+  ALTER COLUMN "from_id" TYPE uuid USING "from_id"::uuid,
+  ALTER COLUMN "to_id" TYPE uuid USING "to_id"::uuid;
+
+ALTER TABLE "slug_history"
+  ALTER COLUMN "entity_id" TYPE uuid USING "entity_id"::uuid;
+
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_author" FOREIGN KEY ("author_id") REFERENCES "author" ("id");
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_category" FOREIGN KEY ("category_id") REFERENCES "book_category" ("id");
+ALTER TABLE "book" ADD CONSTRAINT "fk_book_series" FOREIGN KEY ("series_id") REFERENCES "series" ("id");
+ALTER TABLE "copy" ADD CONSTRAINT "fk_copy_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+ALTER TABLE "loan" ADD CONSTRAINT "fk_loan_copy" FOREIGN KEY ("copy_id") REFERENCES "copy" ("id");
+ALTER TABLE "loan" ADD CONSTRAINT "fk_loan_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+ALTER TABLE "hold" ADD CONSTRAINT "fk_hold_copy" FOREIGN KEY ("copy_id") REFERENCES "copy" ("id");
+ALTER TABLE "fine" ADD CONSTRAINT "fk_fine_member" FOREIGN KEY ("member_id") REFERENCES "member" ("id");
+ALTER TABLE "fine" ADD CONSTRAINT "fk_fine_loan" FOREIGN KEY ("loan_id") REFERENCES "loan" ("id");
+ALTER TABLE "review" ADD CONSTRAINT "fk_review_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id");
+ALTER TABLE "review" ADD CONSTRAINT "fk_review_reviewer" FOREIGN KEY ("reviewer_id") REFERENCES "member" ("id");
+ALTER TABLE "book_cover" ADD CONSTRAINT "fk_book_cover_book" FOREIGN KEY ("book_id") REFERENCES "book" ("id") ON DELETE CASCADE;
+ALTER TABLE "author_redirect" ADD CONSTRAINT "fk_author_redirect_author" FOREIGN KEY ("to_id") REFERENCES "author" ("id") ON DELETE CASCADE;
@@ -0,0 +1,22 @@
+CREATE TABLE "audit_log" (
+  "id" bigserial PRIMARY KEY,
+  "entity" varchar NOT NULL,
+  "entity_id" uuid NOT NULL,
+  "version" int NOT NULL,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "before" jsonb,
+  "after" jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("entity", "entity_id", "version")
+);
+
+-- Entries are never changed or removed once written.
+CREATE FUNCTION "audit_log_append_only"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_log_append_only" BEFORE UPDATE OR DELETE ON "audit_log"
+  FOR EACH ROW EXECUTE PROCEDURE "audit_log_append_only"();
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "book_category_row_version" ON "book_category";
+DROP TRIGGER IF EXISTS "author_row_version" ON "author";
+DROP TRIGGER IF EXISTS "book_row_version" ON "book";
+DROP FUNCTION IF EXISTS "record_row_version"();
+DROP TABLE IF EXISTS "row_version";
@@ -0,0 +1,45 @@
+-- Every version of the book, author and book_category rows, valid from valid_from until
+-- valid_to, which is NULL for the current version and set when the row is changed or
+-- deleted. The rows are kept as JSON so later columns need no change here.
+CREATE TABLE "row_version" (
+  "entity" varchar NOT NULL,
+  "entity_id" uuid NOT NULL,
+  "data" jsonb NOT NULL,
+  "valid_from" timestamptz NOT NULL,
+  "valid_to" timestamptz
+);
+
+CREATE INDEX "idx_row_version_entity_id" ON "row_version" ("entity", "entity_id", "valid_from");
+CREATE INDEX "idx_row_version_valid" ON "row_version" ("entity", "valid_from", "valid_to");
+
+CREATE FUNCTION "record_row_version"() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    UPDATE "row_version" SET "valid_to" = now()
+    WHERE "entity" = TG_TABLE_NAME AND "entity_id" = OLD."id" AND "valid_to" IS NULL;
+  END IF;
+
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    INSERT INTO "row_version" ("entity", "entity_id", "data", "valid_from")
+    VALUES (TG_TABLE_NAME, NEW."id", to_jsonb(NEW), now());
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "book_row_version" AFTER INSERT OR UPDATE OR DELETE ON "book"
+  FOR EACH ROW EXECUTE PROCEDURE "record_row_version"();
+CREATE TRIGGER "author_row_version" AFTER INSERT OR UPDATE OR DELETE ON "author"
+  FOR EACH ROW EXECUTE PROCEDURE "record_row_version"();
+CREATE TRIGGER "book_category_row_version" AFTER INSERT OR UPDATE OR DELETE ON "book_category"
+  FOR EACH ROW EXECUTE PROCEDURE "record_row_version"();
+
+-- Earlier versions of existing rows are unknown, so their current version starts at their
+-- last update.
+INSERT INTO "row_version" ("entity", "entity_id", "data", "valid_from")
+SELECT 'book', "id", to_jsonb(t), "updated_at" FROM "book" t;
+INSERT INTO "row_version" ("entity", "entity_id", "data", "valid_from")
+SELECT 'author', "id", to_jsonb(t), "updated_at" FROM "author" t;
+INSERT INTO "row_version" ("entity", "entity_id", "data", "valid_from")
+SELECT 'book_category', "id", to_jsonb(t), "updated_at" FROM "book_category" t;
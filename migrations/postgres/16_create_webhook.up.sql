@@ -0,0 +1,57 @@
+CREATE TABLE "webhook" (
+  "id" uuid PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "events" text[] NOT NULL DEFAULT '{}',
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Catalog events written in the transaction of the change they describe and fanned out
+-- to the webhooks by the dispatcher, which sets dispatched_at.
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "entity" varchar NOT NULL,
+  "entity_id" uuid NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "dispatched_at" timestamptz
+);
+
+CREATE INDEX "idx_outbox_undispatched" ON "outbox" ("id") WHERE "dispatched_at" IS NULL;
+
+CREATE TABLE "webhook_delivery" (
+  "id" uuid PRIMARY KEY,
+  "webhook_id" uuid NOT NULL,
+  "event_id" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_status_code" int,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_delivery" ADD CONSTRAINT "fk_webhook_delivery_webhook" FOREIGN KEY ("webhook_id") REFERENCES "webhook" ("id") ON DELETE CASCADE;
+ALTER TABLE "webhook_delivery" ADD CONSTRAINT "fk_webhook_delivery_event" FOREIGN KEY ("event_id") REFERENCES "outbox" ("id");
+
+CREATE INDEX "idx_webhook_delivery_due" ON "webhook_delivery" ("next_attempt_at") WHERE "status" = 'pending';
+CREATE INDEX "idx_webhook_delivery_webhook_id" ON "webhook_delivery" ("webhook_id", "created_at");
+
+CREATE TABLE "webhook_attempt" (
+  "id" bigserial PRIMARY KEY,
+  "delivery_id" uuid NOT NULL,
+  "attempt" int NOT NULL,
+  "status_code" int,
+  "error" varchar NOT NULL DEFAULT '',
+  "duration_ms" int NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_attempt" ADD CONSTRAINT "fk_webhook_attempt_delivery" FOREIGN KEY ("delivery_id") REFERENCES "webhook_delivery" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "idx_webhook_attempt_delivery_id" ON "webhook_attempt" ("delivery_id", "attempt");
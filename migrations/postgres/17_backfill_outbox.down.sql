@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_outbox_entity_id";
+
+DELETE FROM "outbox" o
+WHERE o.payload->>'actor' = 'migration'
+  AND NOT EXISTS (SELECT 1 FROM "webhook_delivery" d WHERE d.event_id = o.id);
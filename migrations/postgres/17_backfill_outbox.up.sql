@@ -0,0 +1,31 @@
+-- The outbox is also the change log offline clients sync from. Rows that predate it get
+-- a created event so a client syncing from scratch sees them. They are marked dispatched
+-- so webhooks are not sent for them.
+INSERT INTO "outbox" ("event_type", "entity", "entity_id", "payload", "created_at", "dispatched_at")
+SELECT
+  t.entity || '.created',
+  t.entity,
+  t.id,
+  jsonb_build_object(
+    'type', t.entity || '.created',
+    'entity', t.entity,
+    'entity_id', t.id,
+    'version', COALESCE((SELECT max(a.version) FROM "audit_log" a WHERE a.entity = t.entity AND a.entity_id = t.id), 0),
+    'actor', 'migration',
+    'occurred_at', t.created_at,
+    'before', NULL,
+    'after', t.data - 'updated_at'
+  ),
+  now(),
+  now()
+FROM (
+  SELECT 'book_category' AS entity, id, created_at, updated_at, to_jsonb(c) AS data FROM "book_category" c
+  UNION ALL
+  SELECT 'author', id, created_at, updated_at, to_jsonb(a) FROM "author" a
+  UNION ALL
+  SELECT 'book', id, created_at, updated_at, to_jsonb(b) FROM "book" b
+) t
+WHERE NOT EXISTS (SELECT 1 FROM "outbox" o WHERE o.entity = t.entity AND o.entity_id = t.id)
+ORDER BY t.updated_at, t.id;
+
+CREATE INDEX "idx_outbox_entity_id" ON "outbox" ("entity", "entity_id");
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Audit actions recorded for an entity.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditMerge  = "merge"
+	AuditRevert = "revert"
+)
+
+// AuditEntry is one change of an entity. Before and After hold only the fields that
+// changed: After is empty for a delete and Before is empty for a create.
+type AuditEntry struct {
+	ID        int64                  `json:"id" db:"id" example:"42"`
+	Entity    string                 `json:"entity" db:"entity" example:"book"`
+	EntityID  string                 `json:"entity_id" db:"entity_id" example:"uuid1234"`
+	Version   int                    `json:"version" db:"version" example:"3"`
+	Actor     string                 `json:"actor" db:"actor" example:"librarian@example.com"`
+	Action    string                 `json:"action" db:"action" enums:"create,update,delete,merge,revert" example:"update"`
+	Before    map[string]interface{} `json:"before" db:"before"`
+	After     map[string]interface{} `json:"after" db:"after"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
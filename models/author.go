@@ -3,19 +3,55 @@ package models
 import "time"
 
 type Author struct {
-	ID        string    `json:"id" db:"id" binding:"required" example:"uuid1234"`
-	Firstname string    `json:"firstname" db:"firstname" binding:"required" example:"John"`
-	Lastname  string    `json:"lastname" db:"lastname" binding:"required" example:"Doe"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID          string    `json:"id" db:"id" binding:"required" example:"uuid1234"`
+	Slug        string    `json:"slug" db:"slug" example:"john-doe"`
+	Firstname   string    `json:"firstname" db:"firstname" binding:"required" example:"John"`
+	Lastname    string    `json:"lastname" db:"lastname" binding:"required" example:"Doe"`
+	DisplayName string    `json:"display_name" db:"display_name" example:"John Doe"`
+	SortName    string    `json:"sort_name" db:"sort_name" example:"Doe, John"`
+	BirthDate   string    `json:"birth_date" db:"birth_date" example:"1892-01-03"`
+	DeathDate   string    `json:"death_date" db:"death_date" example:"1973-09-02"`
+	Nationality string    `json:"nationality" db:"nationality" example:"GB"`
+	Biography   string    `json:"biography" db:"biography" example:"English writer and philologist."`
+	Aliases     []string  `json:"aliases" db:"aliases" example:"J. R. R. Tolkien"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type CreateAuthor struct {
-	Firstname string `json:"firstname" db:"firstname" binding:"required" example:"John"`
-	Lastname  string `json:"lastname" db:"lastname" binding:"required" example:"Doe"`
+	Firstname   string   `json:"firstname" db:"firstname" binding:"required" example:"John"`
+	Lastname    string   `json:"lastname" db:"lastname" binding:"required" example:"Doe"`
+	BirthDate   string   `json:"birth_date" db:"birth_date" binding:"omitempty,datetime=2006-01-02" example:"1892-01-03"`
+	DeathDate   string   `json:"death_date" db:"death_date" binding:"omitempty,datetime=2006-01-02" example:"1973-09-02"`
+	Nationality string   `json:"nationality" db:"nationality" binding:"omitempty,iso3166_1_alpha2" example:"GB"`
+	Biography   string   `json:"biography" db:"biography" example:"English writer and philologist."`
+	Aliases     []string `json:"aliases" db:"aliases" binding:"omitempty,dive,required" example:"J. R. R. Tolkien"`
 }
 
 type UpdateAuthor struct {
-	Firstname string `json:"firstname" db:"firstname" example:"John Updated"`
-	Lastname  string `json:"lastname" db:"lastname" example:"Doe Updated"`
+	Firstname   string   `json:"firstname" db:"firstname" example:"John Updated"`
+	Lastname    string   `json:"lastname" db:"lastname" example:"Doe Updated"`
+	BirthDate   string   `json:"birth_date" db:"birth_date" binding:"omitempty,datetime=2006-01-02" example:"1892-01-03"`
+	DeathDate   string   `json:"death_date" db:"death_date" binding:"omitempty,datetime=2006-01-02" example:"1973-09-02"`
+	Nationality string   `json:"nationality" db:"nationality" binding:"omitempty,iso3166_1_alpha2" example:"GB"`
+	Biography   string   `json:"biography" db:"biography" example:"Updated biography"`
+	Aliases     []string `json:"aliases" db:"aliases" binding:"omitempty,dive,required" example:"J. R. R. Tolkien"`
+}
+
+// AuthorDuplicate is a pair of authors that are likely the same person.
+type AuthorDuplicate struct {
+	Author    Author  `json:"author"`
+	Duplicate Author  `json:"duplicate"`
+	Score     float64 `json:"score" example:"0.92"`
+}
+
+type MergeAuthors struct {
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,dive,required" example:"uuid5678"`
+}
+
+// AuthorMerge reports the outcome of merging source authors into a target.
+type AuthorMerge struct {
+	TargetID   string   `json:"target_id" example:"uuid1234"`
+	MergedIDs  []string `json:"merged_ids" example:"uuid5678"`
+	BooksMoved int64    `json:"books_moved" example:"3"`
 }
@@ -2,23 +2,68 @@ package models
 
 import "time"
 
+const (
+	BookFormatHardcover = "hardcover"
+	BookFormatPaperback = "paperback"
+	BookFormatEbook     = "ebook"
+	BookFormatAudiobook = "audiobook"
+)
+
+var BookFormats = []string{BookFormatHardcover, BookFormatPaperback, BookFormatEbook, BookFormatAudiobook}
+
 type Book struct {
-	ID         string    `json:"id" db:"id" example:"uuid1234"`
-	BookName   string    `json:"book_name" db:"name" binding:"required" example:"book name"`
-	AuthorID   string    `json:"author_id" db:"author_id" binding:"required"`
-	CategoryID string    `json:"category_id" db:"category_id" binding:"required" example:"uuid1234"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID              string    `json:"id" db:"id" example:"uuid1234"`
+	Slug            string    `json:"slug" db:"slug" example:"the-hobbit"`
+	BookName        string    `json:"book_name" db:"name" binding:"required" example:"book name"`
+	AuthorID        string    `json:"author_id" db:"author_id" binding:"required"`
+	CategoryID      string    `json:"category_id" db:"category_id" binding:"required" example:"uuid1234"`
+	PublicationYear *int      `json:"publication_year" db:"publication_year" example:"1937"`
+	Language        string    `json:"language" db:"language" example:"en"`
+	PageCount       *int      `json:"page_count" db:"page_count" example:"310"`
+	Description     string    `json:"description" db:"description" example:"A hobbit goes on an unexpected journey."`
+	Edition         string    `json:"edition" db:"edition" example:"1st"`
+	Format          string    `json:"format" db:"format" example:"hardcover"`
+	SeriesID        *string   `json:"series_id" db:"series_id" example:"uuid1234"`
+	SeriesPosition  *float64  `json:"series_position" db:"series_position" example:"2.5"`
+	AverageRating   float64   `json:"average_rating" db:"average_rating" example:"4.25"`
+	RatingCount     int       `json:"rating_count" db:"rating_count" example:"12"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	Previous     *BookSeriesLink   `json:"previous,omitempty"`
+	Next         *BookSeriesLink   `json:"next,omitempty"`
+	Availability *CopyAvailability `json:"availability,omitempty"`
 }
 
 type CreateBook struct {
-	CategoryID string `json:"category_id" db:"category_id" binding:"required" example:"uuid1234"`
-	AuthorID   string `json:"author_id" db:"author_id" binding:"required" example:"author_id"`
-	BookName   string `json:"book_name" db:"book_name" binding:"required" example:"bookname"`
+	CategoryID      string `json:"category_id" db:"category_id" binding:"required" example:"uuid1234"`
+	AuthorID        string `json:"author_id" db:"author_id" binding:"required" example:"author_id"`
+	BookName        string `json:"book_name" db:"book_name" binding:"required" example:"bookname"`
+	PublicationYear *int   `json:"publication_year" db:"publication_year" binding:"omitempty,publication_year" example:"1937"`
+	Language        string `json:"language" db:"language" binding:"omitempty,iso639_1" example:"en"`
+	PageCount       *int   `json:"page_count" db:"page_count" binding:"omitempty,gt=0" example:"310"`
+	Description     string `json:"description" db:"description" example:"A hobbit goes on an unexpected journey."`
+	Edition         string `json:"edition" db:"edition" example:"1st"`
+	Format          string `json:"format" db:"format" binding:"omitempty,oneof=hardcover paperback ebook audiobook" enums:"hardcover,paperback,ebook,audiobook" example:"hardcover"`
 }
 
 type UpdateBook struct {
-	AuthorID   string `json:"author_id" db:"author_id" example:"uuid1234"`
-	CategoryID string `json:"category_id" db:"category_id" example:"uuid1234"`
-	BookName   string `json:"book_name" db:"book_name" example:"Book Name Updated"`
+	AuthorID        string `json:"author_id" db:"author_id" example:"uuid1234"`
+	CategoryID      string `json:"category_id" db:"category_id" example:"uuid1234"`
+	BookName        string `json:"book_name" db:"book_name" example:"Book Name Updated"`
+	PublicationYear *int   `json:"publication_year" db:"publication_year" binding:"omitempty,publication_year" example:"1937"`
+	Language        string `json:"language" db:"language" binding:"omitempty,iso639_1" example:"en"`
+	PageCount       *int   `json:"page_count" db:"page_count" binding:"omitempty,gt=0" example:"310"`
+	Description     string `json:"description" db:"description" example:"Updated description"`
+	Edition         string `json:"edition" db:"edition" example:"2nd"`
+	Format          string `json:"format" db:"format" binding:"omitempty,oneof=hardcover paperback ebook audiobook" enums:"hardcover,paperback,ebook,audiobook" example:"paperback"`
+}
+
+type BookQueryParamModel struct {
+	ApplicationQueryParamModel
+	Language string `json:"language"`
+	Format   string `json:"format"`
+	YearFrom int    `json:"year_from"`
+	YearTo   int    `json:"year_to"`
+	Sort     string `json:"sort"`
 }
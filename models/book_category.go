@@ -4,6 +4,7 @@ import "time"
 
 type BookCategory struct {
 	ID           string    `json:"id" db:"id" example:"uuid1234"`
+	Slug         string    `json:"slug" db:"slug" example:"psychology"`
 	CategoryName string    `json:"category_name" db:"category_name" binding:"required" example:"psychology"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
@@ -1,13 +1,24 @@
 package models
 
+import "time"
+
 type Response struct {
 	Error   string
 	Message string
 	Data    interface{}
 }
 
+// BatchResponse is the result of a read by ids: the entities found, in the order they
+// were asked for, and the ids that matched nothing.
+type BatchResponse struct {
+	Items    interface{} `json:"items"`
+	NotFound []string    `json:"not_found"`
+}
+
 type ApplicationQueryParamModel struct {
-	Search string `json:"search"`
-	Offset int    `json:"offset" default:"0"`
-	Limit  int    `json:"limit" default:"10"`
+	Search string    `json:"search"`
+	Offset int       `json:"offset" default:"0"`
+	Limit  int       `json:"limit" default:"10"`
+	Fields []string  `json:"fields"`
+	AsOf   time.Time `json:"as_of"`
 }
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusOnHold    = "on_hold"
+	CopyStatusLost      = "lost"
+	CopyStatusWithdrawn = "withdrawn"
+)
+
+const (
+	CopyConditionNew     = "new"
+	CopyConditionGood    = "good"
+	CopyConditionFair    = "fair"
+	CopyConditionPoor    = "poor"
+	CopyConditionDamaged = "damaged"
+)
+
+type Copy struct {
+	ID              string    `json:"id" db:"id" example:"uuid1234"`
+	BookID          string    `json:"book_id" db:"book_id" example:"uuid1234"`
+	Barcode         string    `json:"barcode" db:"barcode" example:"31234000123456"`
+	Condition       string    `json:"condition" db:"condition" example:"good"`
+	ShelfLocation   string    `json:"shelf_location" db:"shelf_location" example:"B2-14"`
+	AcquisitionDate string    `json:"acquisition_date" db:"acquisition_date" example:"2022-08-01"`
+	Status          string    `json:"status" db:"status" example:"available"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateCopy struct {
+	Barcode         string `json:"barcode" db:"barcode" binding:"required" example:"31234000123456"`
+	Condition       string `json:"condition" db:"condition" binding:"omitempty,oneof=new good fair poor damaged" enums:"new,good,fair,poor,damaged" example:"good"`
+	ShelfLocation   string `json:"shelf_location" db:"shelf_location" example:"B2-14"`
+	AcquisitionDate string `json:"acquisition_date" db:"acquisition_date" binding:"omitempty,datetime=2006-01-02" example:"2022-08-01"`
+	Status          string `json:"status" db:"status" binding:"omitempty,oneof=available lost withdrawn" enums:"available,lost,withdrawn" example:"available"`
+}
+
+type UpdateCopy struct {
+	Barcode         string `json:"barcode" db:"barcode" example:"31234000123456"`
+	Condition       string `json:"condition" db:"condition" binding:"omitempty,oneof=new good fair poor damaged" enums:"new,good,fair,poor,damaged" example:"fair"`
+	ShelfLocation   string `json:"shelf_location" db:"shelf_location" example:"C1-02"`
+	AcquisitionDate string `json:"acquisition_date" db:"acquisition_date" binding:"omitempty,datetime=2006-01-02" example:"2022-08-01"`
+	Status          string `json:"status" db:"status" binding:"omitempty,oneof=available lost withdrawn" enums:"available,lost,withdrawn" example:"lost"`
+}
+
+// CopyAvailability counts the physical copies of a book by status.
+type CopyAvailability struct {
+	Total     int `json:"total" example:"4"`
+	Available int `json:"available" example:"2"`
+	OnLoan    int `json:"on_loan" example:"1"`
+	OnHold    int `json:"on_hold" example:"0"`
+	Lost      int `json:"lost" example:"0"`
+	Withdrawn int `json:"withdrawn" example:"1"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	CoverSizeOriginal = "original"
+	CoverSizeMedium   = "medium"
+	CoverSizeSmall    = "small"
+)
+
+// Cover describes the cover image stored for a book. The image bytes live in blob storage.
+type Cover struct {
+	BookID      string    `json:"book_id" db:"book_id" example:"uuid1234"`
+	ContentType string    `json:"content_type" db:"content_type" example:"image/jpeg"`
+	ETag        string    `json:"etag" db:"etag" example:"9f86d081884c7d65"`
+	Size        int64     `json:"size" db:"size" example:"183204"`
+	Width       int       `json:"width" db:"width" example:"800"`
+	Height      int       `json:"height" db:"height" example:"1200"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Event describes one change of a book, author or book category. It is written to the
+// outbox with the change and is what webhooks receive. Before and After hold the changed
+// fields, as in the audit log.
+type Event struct {
+	Type       string                 `json:"type" example:"book.updated"`
+	Entity     string                 `json:"entity" example:"book"`
+	EntityID   string                 `json:"entity_id" example:"uuid1234"`
+	Version    int                    `json:"version" example:"3"`
+	Actor      string                 `json:"actor" example:"librarian@example.com"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Before     map[string]interface{} `json:"before"`
+	After      map[string]interface{} `json:"after"`
+}
+
+// StoredEvent is an Event as kept in the outbox. The id orders the events and is what
+// feeds resume from.
+type StoredEvent struct {
+	ID      int64
+	Type    string
+	Entity  string
+	Payload []byte
+}
+
+// Notification announces a committed catalog event to every API instance. It carries
+// only what fits a Postgres NOTIFY; the full event is in the outbox under ID. Resync
+// means notifications may have been lost and anything derived from them should be reloaded.
+type Notification struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Entity   string `json:"entity"`
+	EntityID string `json:"entity_id"`
+	Resync   bool   `json:"resync,omitempty"`
+}
+
+// EventEntities lists the entities events are emitted for.
+var EventEntities = []string{"book", "author", "book_category"}
+
+// EventTypes lists every event type, "<entity>.<past tense action>".
+var EventTypes = []string{
+	"book.created",
+	"book.updated",
+	"book.deleted",
+	"book.reverted",
+	"author.created",
+	"author.updated",
+	"author.deleted",
+	"author.merged",
+	"book_category.created",
+	"book_category.updated",
+	"book_category.deleted",
+}
+
+// EventType names the event for an audit action on entity, e.g. "book.updated".
+func EventType(entity, action string) string {
+	switch action {
+	case AuditRevert:
+		return entity + ".reverted"
+	default:
+		return entity + "." + action + "d"
+	}
+}
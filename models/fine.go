@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+const (
+	FineKindCharge  = "charge"
+	FineKindPayment = "payment"
+	FineKindWaiver  = "waiver"
+)
+
+// Fine is one entry of a member's fine ledger. Amounts are in minor currency units;
+// charges raise the balance, payments and waivers lower it.
+type Fine struct {
+	ID        string    `json:"id" db:"id" example:"uuid1234"`
+	MemberID  string    `json:"member_id" db:"member_id" example:"uuid1234"`
+	LoanID    *string   `json:"loan_id" db:"loan_id" example:"uuid1234"`
+	Kind      string    `json:"kind" db:"kind" example:"charge"`
+	Amount    int64     `json:"amount" db:"amount" example:"1500"`
+	Note      string    `json:"note" db:"note" example:"3 days overdue"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateFine struct {
+	Kind   string `json:"kind" db:"kind" binding:"required,oneof=payment waiver" enums:"payment,waiver" example:"payment"`
+	Amount int64  `json:"amount" db:"amount" binding:"required,gt=0" example:"1500"`
+	Note   string `json:"note" db:"note" example:"paid at the front desk"`
+}
+
+type FineAccount struct {
+	MemberID string `json:"member_id" example:"uuid1234"`
+	Balance  int64  `json:"balance" example:"1500"`
+	Entries  []Fine `json:"entries"`
+}
+
+type AccruedFine struct {
+	LoanID      string `json:"loan_id" example:"uuid1234"`
+	OverdueDays int    `json:"overdue_days" example:"3"`
+	Amount      int64  `json:"amount" example:"1500"`
+	Final       bool   `json:"final" example:"false"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+type Hold struct {
+	ID              string     `json:"id" db:"id" example:"uuid1234"`
+	BookID          string     `json:"book_id" db:"book_id" example:"uuid1234"`
+	MemberID        string     `json:"member_id" db:"member_id" example:"uuid1234"`
+	CopyID          *string    `json:"copy_id" db:"copy_id" example:"uuid1234"`
+	Status          string     `json:"status" db:"status" example:"waiting"`
+	QueuePosition   *int       `json:"queue_position,omitempty" example:"2"`
+	PickupExpiresAt *time.Time `json:"pickup_expires_at" db:"pickup_expires_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type CreateHold struct {
+	MemberID string `json:"member_id" db:"member_id" binding:"required" example:"uuid1234"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type Loan struct {
+	ID           string     `json:"id" db:"id" example:"uuid1234"`
+	CopyID       string     `json:"copy_id" db:"copy_id" example:"uuid1234"`
+	BookID       string     `json:"book_id" db:"book_id" example:"uuid1234"`
+	MemberID     string     `json:"member_id" db:"member_id" example:"uuid1234"`
+	CheckedOutAt time.Time  `json:"checked_out_at" db:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at" db:"due_at"`
+	ReturnedAt   *time.Time `json:"returned_at" db:"returned_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type CreateLoan struct {
+	Barcode  string `json:"barcode" db:"barcode" binding:"required" example:"31234000123456"`
+	MemberID string `json:"member_id" db:"member_id" binding:"required" example:"uuid1234"`
+}
+
+type LoanQueryParamModel struct {
+	ApplicationQueryParamModel
+	MemberID   string `json:"member_id"`
+	ActiveOnly bool   `json:"active"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type Member struct {
+	ID        string    `json:"id" db:"id" example:"uuid1234"`
+	Firstname string    `json:"firstname" db:"firstname" example:"Jane"`
+	Lastname  string    `json:"lastname" db:"lastname" example:"Doe"`
+	Email     string    `json:"email" db:"email" example:"jane@example.com"`
+	Phone     string    `json:"phone" db:"phone" example:"+998901234567"`
+	MaxLoans  int       `json:"max_loans" db:"max_loans" example:"5"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateMember struct {
+	Firstname string `json:"firstname" db:"firstname" binding:"required" example:"Jane"`
+	Lastname  string `json:"lastname" db:"lastname" binding:"required" example:"Doe"`
+	Email     string `json:"email" db:"email" binding:"required,email" example:"jane@example.com"`
+	Phone     string `json:"phone" db:"phone" example:"+998901234567"`
+	MaxLoans  int    `json:"max_loans" db:"max_loans" binding:"omitempty,gt=0" example:"5"`
+}
+
+type UpdateMember struct {
+	Firstname string `json:"firstname" db:"firstname" example:"Jane Updated"`
+	Lastname  string `json:"lastname" db:"lastname" example:"Doe Updated"`
+	Email     string `json:"email" db:"email" binding:"omitempty,email" example:"jane.doe@example.com"`
+	Phone     string `json:"phone" db:"phone" example:"+998901234567"`
+	MaxLoans  int    `json:"max_loans" db:"max_loans" binding:"omitempty,gt=0" example:"8"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type Review struct {
+	ID         string    `json:"id" db:"id" example:"uuid1234"`
+	BookID     string    `json:"book_id" db:"book_id" example:"uuid1234"`
+	ReviewerID string    `json:"reviewer_id" db:"reviewer_id" example:"uuid1234"`
+	Rating     int       `json:"rating" db:"rating" example:"5"`
+	Text       string    `json:"text" db:"text" example:"Could not put it down."`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateReview struct {
+	ReviewerID string `json:"reviewer_id" db:"reviewer_id" binding:"required" example:"uuid1234"`
+	Rating     int    `json:"rating" db:"rating" binding:"required,min=1,max=5" example:"5"`
+	Text       string `json:"text" db:"text" example:"Could not put it down."`
+}
+
+type UpdateReview struct {
+	Rating int    `json:"rating" db:"rating" binding:"omitempty,min=1,max=5" example:"4"`
+	Text   string `json:"text" db:"text" example:"Still good on a second read."`
+}
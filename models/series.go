@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type Series struct {
+	ID          string    `json:"id" db:"id" example:"uuid1234"`
+	SeriesName  string    `json:"series_name" db:"series_name" binding:"required" example:"The Lord of the Rings"`
+	Description string    `json:"description" db:"description" example:"Epic high fantasy trilogy"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateSeries struct {
+	SeriesName  string `json:"series_name" db:"series_name" binding:"required" example:"The Lord of the Rings"`
+	Description string `json:"description" db:"description" example:"Epic high fantasy trilogy"`
+}
+
+type UpdateSeries struct {
+	SeriesName  string `json:"series_name" db:"series_name" example:"The Lord of the Rings Updated"`
+	Description string `json:"description" db:"description" example:"Updated description"`
+}
+
+type SeriesMembership struct {
+	Position float64 `json:"position" db:"series_position" binding:"required,gt=0" example:"2.5"`
+}
+
+// BookSeriesLink points at a neighbouring book within the same series.
+type BookSeriesLink struct {
+	ID             string  `json:"id" db:"id" example:"uuid1234"`
+	BookName       string  `json:"book_name" db:"book_name" example:"The Two Towers"`
+	SeriesPosition float64 `json:"series_position" db:"series_position" example:"2"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// EntityChange names an entity changed after a point of the change log, with the sequence
+// number of its latest change.
+type EntityChange struct {
+	Seq      int64
+	Entity   string
+	EntityID string
+}
+
+// SyncChange is one entity in a sync page: its current state, or a tombstone when it has
+// been deleted.
+type SyncChange struct {
+	Entity  string      `json:"entity" example:"book"`
+	ID      string      `json:"id" example:"uuid1234"`
+	Deleted bool        `json:"deleted"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// SyncPage is a page of changes since a sync token. Clients pass NextToken to the next
+// sync, whether or not HasMore is set.
+type SyncPage struct {
+	Changes   []SyncChange `json:"changes"`
+	NextToken string       `json:"next_token" example:"MTIz"`
+	HasMore   bool         `json:"has_more"`
+}
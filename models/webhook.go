@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses. A delivery that keeps failing is dead-lettered once it runs
+// out of attempts and is only retried on request.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook receives the catalog events it subscribes to. No events means all of them. The
+// secret signs every delivery and is only shown when the webhook is created.
+type Webhook struct {
+	ID        string    `json:"id" db:"id" example:"uuid1234"`
+	URL       string    `json:"url" db:"url" example:"https://search.example.com/hooks/catalog"`
+	Secret    string    `json:"secret,omitempty" db:"secret" example:"3f9a0c..."`
+	Events    []string  `json:"events" db:"events" example:"book.created"`
+	Active    bool      `json:"active" db:"active" example:"true"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateWebhook struct {
+	URL    string   `json:"url" db:"url" binding:"required,url,webhook_url" example:"https://search.example.com/hooks/catalog"`
+	Secret string   `json:"secret" db:"secret" binding:"omitempty,min=16" example:"a-long-shared-secret"`
+	Events []string `json:"events" db:"events" binding:"omitempty,dive,required" example:"book.created"`
+	Active *bool    `json:"active" db:"active" example:"true"`
+}
+
+type UpdateWebhook struct {
+	URL    string   `json:"url" db:"url" binding:"omitempty,url,webhook_url" example:"https://search.example.com/hooks/catalog"`
+	Secret string   `json:"secret" db:"secret" binding:"omitempty,min=16" example:"a-new-shared-secret"`
+	Events []string `json:"events" db:"events" binding:"omitempty,dive,required" example:"book.updated"`
+	Active *bool    `json:"active" db:"active" example:"false"`
+}
+
+// WebhookDelivery is the delivery of one event to one webhook, with its attempts when
+// fetched on its own.
+type WebhookDelivery struct {
+	ID             string           `json:"id" db:"id" example:"uuid1234"`
+	WebhookID      string           `json:"webhook_id" db:"webhook_id" example:"uuid1234"`
+	EventID        int64            `json:"event_id" db:"event_id" example:"1042"`
+	EventType      string           `json:"event_type" db:"event_type" example:"book.updated"`
+	Status         string           `json:"status" db:"status" enums:"pending,delivered,dead" example:"pending"`
+	Attempts       int              `json:"attempts" db:"attempts" example:"2"`
+	NextAttemptAt  *time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode *int             `json:"last_status_code" db:"last_status_code" example:"503"`
+	LastError      string           `json:"last_error" db:"last_error" example:"unexpected status 503"`
+	DeliveredAt    *time.Time       `json:"delivered_at" db:"delivered_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+	AttemptLog     []WebhookAttempt `json:"attempt_log,omitempty"`
+}
+
+// WebhookAttempt is one try at a delivery.
+type WebhookAttempt struct {
+	Attempt    int       `json:"attempt" db:"attempt" example:"1"`
+	StatusCode *int      `json:"status_code" db:"status_code" example:"503"`
+	Error      string    `json:"error" db:"error" example:"unexpected status 503"`
+	DurationMS int       `json:"duration_ms" db:"duration_ms" example:"124"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// DueDelivery is a delivery the dispatcher has claimed, with what it needs to send it.
+type DueDelivery struct {
+	ID        string
+	WebhookID string
+	EventID   int64
+	EventType string
+	URL       string
+	Secret    string
+	Attempts  int
+	Payload   []byte
+}
+
+type WebhookDeliveryQueryParamModel struct {
+	ApplicationQueryParamModel
+	Status string `json:"status"`
+}
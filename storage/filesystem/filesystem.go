@@ -0,0 +1,88 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saidakhmatov/catalog_of_books/storage"
+)
+
+type fileSystem struct {
+	root string
+}
+
+// NewFileSystem keeps blobs as plain files below root.
+func NewFileSystem(root string) storage.BlobStorageI {
+	return &fileSystem{
+		root: root,
+	}
+}
+
+// path maps a key to a file below root, refusing keys that would escape it.
+func (f *fileSystem) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid blob key")
+	}
+
+	return filepath.Join(f.root, filepath.FromSlash(clean)), nil
+}
+
+// Put writes through a temporary file so readers never see a partially written blob.
+func (f *fileSystem) Put(key string, data []byte) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (f *fileSystem) Get(key string) ([]byte, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, storage.ErrBlobNotFound
+	}
+
+	return data, err
+}
+
+func (f *fileSystem) Delete(key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+type auditRepo struct {
+	db *sqlx.DB
+}
+
+// auditIgnored lists the columns left out of audit entries: updated_at changes with every
+// write and the entry carries its own timestamp.
+var auditIgnored = []string{"updated_at"}
+
+// outboxLock is the advisory lock key that serializes outbox writers.
+const outboxLock = 7453201
+
+// lockedRow returns the row id of table as a JSON object keyed by column and locks it for
+// the rest of the transaction. table is always one of the constant table names.
+func lockedRow(tx *sqlx.Tx, table, id string) (map[string]interface{}, error) {
+	var data []byte
+	err := tx.QueryRow(fmt.Sprintf(`SELECT to_jsonb(t) FROM %s t WHERE id = $1 FOR UPDATE`, table), id).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSONObject(data)
+}
+
+func decodeJSONObject(data []byte) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var resp map[string]interface{}
+	if err := decoder.Decode(&resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// recordChange appends a change of the row id of entity to the audit log as the next
+// version of that row. Only the columns that differ between before and after are kept;
+// before is nil for a create and after is nil for a delete. An update that changed
+// nothing is not recorded. The change is also queued in the outbox as an event for the
+// webhooks. It must run in the transaction that made the change.
+func recordChange(tx *sqlx.Tx, entity, id, actor, action string, before, after map[string]interface{}) error {
+	changedBefore := make(map[string]interface{})
+	changedAfter := make(map[string]interface{})
+
+	for column, value := range before {
+		if other, ok := after[column]; after == nil || !ok || !reflect.DeepEqual(value, other) {
+			changedBefore[column] = value
+		}
+	}
+	for column, value := range after {
+		if other, ok := before[column]; before == nil || !ok || !reflect.DeepEqual(value, other) {
+			changedAfter[column] = value
+		}
+	}
+	for _, column := range auditIgnored {
+		delete(changedBefore, column)
+		delete(changedAfter, column)
+	}
+
+	if len(changedBefore) == 0 && len(changedAfter) == 0 {
+		return nil
+	}
+
+	event := models.Event{
+		Type:     models.EventType(entity, action),
+		Entity:   entity,
+		EntityID: id,
+		Actor:    actor,
+	}
+
+	var beforeJSON, afterJSON interface{}
+	if before != nil {
+		event.Before = changedBefore
+		data, err := json.Marshal(changedBefore)
+		if err != nil {
+			return err
+		}
+		beforeJSON = string(data)
+	}
+	if after != nil {
+		event.After = changedAfter
+		data, err := json.Marshal(changedAfter)
+		if err != nil {
+			return err
+		}
+		afterJSON = string(data)
+	}
+
+	err := tx.QueryRow(`INSERT INTO audit_log (entity, entity_id, version, actor, action, before, after)
+		SELECT $1, $2, COALESCE(max(version), 0) + 1, $3, $4, $5::jsonb, $6::jsonb
+		FROM audit_log WHERE entity = $1 AND entity_id = $2
+		RETURNING version, created_at`, entity, id, actor, action, beforeJSON, afterJSON).Scan(&event.Version, &event.OccurredAt)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Readers of the outbox resume after the last id they saw, so ids must become visible
+	// in order. Holding the lock until commit makes ids follow commit order.
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, outboxLock); err != nil {
+		return err
+	}
+
+	notification := models.Notification{
+		Type:     event.Type,
+		Entity:   entity,
+		EntityID: id,
+	}
+
+	err = tx.QueryRow(`INSERT INTO outbox (event_type, entity, entity_id, payload) VALUES ($1, $2, $3, $4) RETURNING id`,
+		event.Type, entity, id, string(payload)).Scan(&notification.ID)
+	if err != nil {
+		return err
+	}
+
+	message, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	// Postgres delivers the notification to the listeners of every instance on commit.
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, EventChannel, string(message))
+
+	return err
+}
+
+// snapshotAt rebuilds the row id of entity as it was right after the given version by
+// replaying the changes recorded up to it. It is sql.ErrNoRows if there is no such
+// version, and an error if the row was deleted at that version.
+func snapshotAt(q sqlx.Queryer, entity, id string, version int) (map[string]interface{}, error) {
+	rows, err := q.Query(`SELECT version, after FROM audit_log
+		WHERE entity = $1 AND entity_id = $2 AND version <= $3
+		ORDER BY version`, entity, id, version)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := make(map[string]interface{})
+	last := 0
+	var deleted bool
+	for rows.Next() {
+		var after []byte
+		if err := rows.Scan(&last, &after); err != nil {
+			return nil, err
+		}
+
+		changes, err := decodeJSONObject(after)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted = changes == nil
+		for column, value := range changes {
+			resp[column] = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if last != version {
+		return nil, sql.ErrNoRows
+	}
+	if deleted {
+		return nil, fmt.Errorf("version %d of the %s is its deletion", version, entity)
+	}
+
+	return resp, nil
+}
+
+// deleteAudited deletes the row id of table and records the deletion. Deleting a row that
+// does not exist affects nothing and records nothing.
+func deleteAudited(db *sqlx.DB, table, id, actor string) (int64, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockedRow(tx, table, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table), id)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := recordChange(tx, table, id, actor, models.AuditDelete, before, nil); err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+// GetHistory returns the changes of the row id of entity, newest first.
+func (r *auditRepo) GetHistory(entity, id string, queryParam models.ApplicationQueryParamModel) ([]models.AuditEntry, error) {
+	var resp []models.AuditEntry = []models.AuditEntry{}
+
+	limit := 10
+	if queryParam.Limit > 0 {
+		limit = queryParam.Limit
+	}
+
+	rows, err := r.db.Query(`SELECT id, entity, entity_id, version, actor, action, before, after, created_at
+		FROM audit_log
+		WHERE entity = $1 AND entity_id = $2
+		ORDER BY version DESC
+		OFFSET $3 LIMIT $4`, entity, id, queryParam.Offset, limit)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after []byte
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Version,
+			&entry.Actor,
+			&entry.Action,
+			&before,
+			&after,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return resp, err
+		}
+
+		if entry.Before, err = decodeJSONObject(before); err != nil {
+			return resp, err
+		}
+		if entry.After, err = decodeJSONObject(after); err != nil {
+			return resp, err
+		}
+
+		resp = append(resp, entry)
+	}
+
+	return resp, rows.Err()
+}
@@ -1,58 +1,199 @@
 package postgres
 
 import (
+	"errors"
+	"time"
+
 	"github.com/saidakhmatov/catalog_of_books/models"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type authorRepo struct {
 	db *sqlx.DB
 }
 
-func (r *authorRepo) CreateAuthor(entity models.Author) (string, error) {
+// authorFieldColumns lists the fields an author query can select, in select list order.
+var authorFieldColumns = []fieldColumn{
+	{"id", []string{"id"}},
+	{"slug", []string{"slug"}},
+	{"firstname", []string{"firstname"}},
+	{"lastname", []string{"lastname"}},
+	{"display_name", []string{"display_name"}},
+	{"sort_name", []string{"sort_name"}},
+	{"birth_date", []string{"COALESCE(to_char(birth_date, 'YYYY-MM-DD'), '')"}},
+	{"death_date", []string{"COALESCE(to_char(death_date, 'YYYY-MM-DD'), '')"}},
+	{"nationality", []string{"nationality"}},
+	{"biography", []string{"biography"}},
+	{"aliases", []string{"aliases"}},
+	{"created_at", []string{"created_at"}},
+	{"updated_at", []string{"updated_at"}},
+}
+
+// allAuthorFields and authorColumns select every field of an author.
+var allAuthorFields, authorColumns = allFields(authorFieldColumns)
+
+func scanAuthor(row rowScanner, selected []fieldColumn) (models.Author, error) {
+	var resp models.Author
+
+	err := scanFields(row, selected, map[string][]interface{}{
+		"id":           {&resp.ID},
+		"slug":         {&resp.Slug},
+		"firstname":    {&resp.Firstname},
+		"lastname":     {&resp.Lastname},
+		"display_name": {&resp.DisplayName},
+		"sort_name":    {&resp.SortName},
+		"birth_date":   {&resp.BirthDate},
+		"death_date":   {&resp.DeathDate},
+		"nationality":  {&resp.Nationality},
+		"biography":    {&resp.Biography},
+		"aliases":      {pq.Array(&resp.Aliases)},
+		"created_at":   {&resp.CreatedAt},
+		"updated_at":   {&resp.UpdatedAt},
+	})
+
+	return resp, err
+}
+
+func (r *authorRepo) CreateAuthor(entity models.Author, actor string) (models.Author, error) {
 	
-	var resp string
+	var resp models.Author
 
-	query := `INSERT INTO author (id, firstname, lastname, created_at, updated_at) VALUES ($1,$2,$3,$4,	$5) RETURNING id; `
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
 
-	row := r.db.QueryRow(query, entity.ID, entity.Firstname, entity.Lastname, entity.CreatedAt, entity.UpdatedAt)
+	slug, err := uniqueSlug(tx, "author", entity.Firstname+" "+entity.Lastname, entity.ID)
+	if err != nil {
+		return resp, err
+	}
 
-	if err := row.Scan(&resp); err != nil {
-		return "", err
+	query := `INSERT INTO author (
+		id,
+		slug,
+		firstname,
+		lastname,
+		birth_date,
+		death_date,
+		nationality,
+		biography,
+		aliases,
+		created_at,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		NULLIF($5, '')::date,
+		NULLIF($6, '')::date,
+		$7,
+		$8,
+		COALESCE($9, '{}'::text[]),
+		$10,
+		$11
+	) RETURNING` + authorColumns
+
+	row := tx.QueryRow(query,
+		entity.ID,
+		slug,
+		entity.Firstname,
+		entity.Lastname,
+		entity.BirthDate,
+		entity.DeathDate,
+		entity.Nationality,
+		entity.Biography,
+		pq.Array(entity.Aliases),
+		entity.CreatedAt,
+		entity.UpdatedAt,
+	)
+
+	resp, err = scanAuthor(row, allAuthorFields)
+	if err != nil {
+		return resp, err
 	}
 
-	return resp, nil
+	after, err := lockedRow(tx, "author", resp.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := recordChange(tx, "author", resp.ID, actor, models.AuditCreate, nil, after); err != nil {
+		return resp, err
+	}
+
+	return resp, tx.Commit()
 }
 
-func (r *authorRepo) GetAuthor(id string) (models.Author, error) {
-	
-	var resp models.Author
+// GetAuthor returns the author with the given id or slug. When fields are given only those
+// are selected and filled in.
+func (r *authorRepo) GetAuthor(id string, fields ...string) (models.Author, error) {
+
+	selected, columns, err := selectFields(authorFieldColumns, fields)
+	if err != nil {
+		return models.Author{}, err
+	}
 
 	query := `
-		SELECT
-			id,
-			firstname,
-			lastname,
-			created_at,
-			updated_at
+		SELECT` + columns + `
 		FROM author
-		WHERE id = $1;
+		WHERE ` + lookupColumn(id) + ` = $1;
 	`
 
-	row := r.db.QueryRow(query, id)
-	
-	if err := row.Scan(
-		&resp.ID,
-		&resp.Firstname,
-		&resp.Lastname,
-		&resp.CreatedAt,
-		&resp.UpdatedAt,
-	); err != nil {
+	return scanAuthor(r.db.QueryRow(query, id), selected)
+}
+
+// GetAuthorsByIDs returns the authors with the given ids in the order the ids are given.
+// Ids without an author are left out. The id is always selected so callers can tell which.
+func (r *authorRepo) GetAuthorsByIDs(ids []string, fields ...string) ([]models.Author, error) {
+	var resp []models.Author = []models.Author{}
+
+	selected, columns, err := selectFields(authorFieldColumns, withFields(fields, "id"))
+	if err != nil {
 		return resp, err
 	}
 
-	return resp, nil
+	query := `SELECT` + columns + `
+	FROM
+		author
+	WHERE id = ANY($1::uuid[])
+	ORDER BY array_position($1::uuid[], id)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		author, err := scanAuthor(rows, selected)
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, author)
+	}
+
+	return resp, rows.Err()
+}
+
+// GetAuthorAsOf returns the author with the given id or slug as it was at asOf. When
+// fields are given only those are selected and filled in.
+func (r *authorRepo) GetAuthorAsOf(id string, asOf time.Time, fields ...string) (models.Author, error) {
+
+	selected, columns, err := selectFields(authorFieldColumns, fields)
+	if err != nil {
+		return models.Author{}, err
+	}
+
+	query := `
+		SELECT` + columns + `
+		FROM ` + tableAsOf("author", "$2") + `
+		WHERE ` + lookupColumn(id) + ` = $1;
+	`
+
+	return scanAuthor(r.db.QueryRow(query, id, asOf), selected)
 }
 
 func (r *authorRepo) GetAllAuthors(queryParam models.ApplicationQueryParamModel) ([]models.Author, error) {
@@ -60,21 +201,32 @@ func (r *authorRepo) GetAllAuthors(queryParam models.ApplicationQueryParamModel)
 
 	params := make(map[string]interface{})
 
-	query := `SELECT
-		id,
-		firstname,
-		lastname,
-		created_at,
-		updated_at
+	selected, columns, err := selectFields(authorFieldColumns, queryParam.Fields)
+	if err != nil {
+		return resp, err
+	}
+
+	source := "author"
+	if !queryParam.AsOf.IsZero() {
+		params["as_of"] = queryParam.AsOf
+		source = tableAsOf("author", ":as_of")
+	}
+
+	query := `SELECT` + columns + `
 	FROM
-		author`
+		` + source
 	filter := " WHERE 1=1"
 	offset := " OFFSET 0"
 	limit := " LIMIT 10"
 
 	if len(queryParam.Search) > 0 {
 		params["search"] = queryParam.Search
-		filter += " AND (firstname ILIKE '%' || :search || '%') OR (lastname ILIKE '%' || :search || '%')"
+		filter += ` AND (
+			firstname ILIKE '%' || :search || '%'
+			OR lastname ILIKE '%' || :search || '%'
+			OR display_name ILIKE '%' || :search || '%'
+			OR EXISTS (SELECT 1 FROM unnest(aliases) alias WHERE alias ILIKE '%' || :search || '%')
+		)`
 	}
 
 	if queryParam.Offset > 0 {
@@ -87,7 +239,7 @@ func (r *authorRepo) GetAllAuthors(queryParam models.ApplicationQueryParamModel)
 		limit = " LIMIT :limit"
 	}
 
-	countQuery := "SELECT count(1) FROM author" + filter
+	countQuery := "SELECT count(1) FROM " + source + filter
 	
 	row, err := r.db.NamedQuery(countQuery, params)
 	
@@ -96,7 +248,7 @@ func (r *authorRepo) GetAllAuthors(queryParam models.ApplicationQueryParamModel)
 	}
 	defer row.Close()
 
-	q := query + filter + offset + limit
+	q := query + filter + " ORDER BY sort_name, id" + offset + limit
 	rows, err := r.db.NamedQuery(q, params)
 	
 	if err != nil {
@@ -105,15 +257,7 @@ func (r *authorRepo) GetAllAuthors(queryParam models.ApplicationQueryParamModel)
 	defer rows.Close()
 	
 	for rows.Next() {
-		var author models.Author
-		err = rows.Scan(
-			&author.ID,
-			&author.Firstname,
-			&author.Lastname,
-			&author.CreatedAt,
-			&author.UpdatedAt,
-		)
-
+		author, err := scanAuthor(rows, selected)
 		if err != nil {
 			return resp, err
 		}
@@ -123,7 +267,9 @@ func (r *authorRepo) GetAllAuthors(queryParam models.ApplicationQueryParamModel)
 	return resp, nil
 }
 
-func (r *authorRepo) UpdateAuthor(entity models.UpdateAuthor, id string) (int64, error) {
+func (r *authorRepo) UpdateAuthor(entity models.UpdateAuthor, id, actor string) (models.Author, error) {
+
+	var resp models.Author
 	
 	params := make(map[string]interface{})
 	
@@ -141,37 +287,250 @@ func (r *authorRepo) UpdateAuthor(entity models.UpdateAuthor, id string) (int64,
 		query += `lastname = :lastname,`
 	}
 
-	query += `updated_at = now() WHERE id =:id`
+	if len(entity.BirthDate) > 0 {
+		params["birth_date"] = entity.BirthDate
+		query += `birth_date = CAST(:birth_date AS date),`
+	}
 
-	result, err := r.db.NamedExec(query, params)
-	
+	if len(entity.DeathDate) > 0 {
+		params["death_date"] = entity.DeathDate
+		query += `death_date = CAST(:death_date AS date),`
+	}
+
+	if len(entity.Nationality) > 0 {
+		params["nationality"] = entity.Nationality
+		query += `nationality = :nationality,`
+	}
+
+	if len(entity.Biography) > 0 {
+		params["biography"] = entity.Biography
+		query += `biography = :biography,`
+	}
+
+	if entity.Aliases != nil {
+		params["aliases"] = pq.Array(entity.Aliases)
+		query += `aliases = :aliases,`
+	}
+
+	query += `updated_at = now() WHERE id =:id RETURNING` + authorColumns
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockedRow(tx, "author", id)
+	if err != nil {
+		return resp, err
+	}
+
+	if len(entity.Firstname) > 0 || len(entity.Lastname) > 0 {
+		firstname, _ := before["firstname"].(string)
+		lastname, _ := before["lastname"].(string)
+
+		if len(entity.Firstname) > 0 {
+			firstname = entity.Firstname
+		}
+		if len(entity.Lastname) > 0 {
+			lastname = entity.Lastname
+		}
+
+		if err := renameSlug(tx, "author", firstname+" "+lastname, id); err != nil {
+			return resp, err
+		}
+	}
+
+	q, args, err := sqlx.Named(query, params)
 	if err != nil {
-		return 0, err
+		return resp, err
+	}
+
+	resp, err = scanAuthor(tx.QueryRowx(tx.Rebind(q), args...), allAuthorFields)
+	if err != nil {
+		return resp, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	after, err := lockedRow(tx, "author", id)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	return rowsAffected, err
+	if err := recordChange(tx, "author", id, actor, models.AuditUpdate, before, after); err != nil {
+		return resp, err
+	}
+
+	return resp, tx.Commit()
 }
 
-func (r *authorRepo) DeleteAuthor(id string) (int64, error) {
-	
-	query := `DELETE FROM author WHERE id = $1`
+func (r *authorRepo) DeleteAuthor(id, actor string) (int64, error) {
+	return deleteAudited(r.db, "author", id, actor)
+}
 
-	result, err := r.db.Exec(query, id)
-	
+// GetAuthorNames returns the id and name of every author, which is all the duplicate
+// detection needs.
+func (r *authorRepo) GetAuthorNames() ([]models.Author, error) {
+	var resp []models.Author = []models.Author{}
+
+	rows, err := r.db.Query(`SELECT id, firstname, lastname, display_name FROM author ORDER BY sort_name, id`)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
-	
+	for rows.Next() {
+		var author models.Author
+		if err := rows.Scan(&author.ID, &author.Firstname, &author.Lastname, &author.DisplayName); err != nil {
+			return resp, err
+		}
+		resp = append(resp, author)
+	}
+
+	return resp, rows.Err()
+}
+
+// GetAuthorRedirect returns the id of the author that id was merged into.
+func (r *authorRepo) GetAuthorRedirect(id string) (string, error) {
+	var resp string
+
+	err := r.db.QueryRow(`SELECT to_id FROM author_redirect WHERE from_id = $1`, id).Scan(&resp)
+
+	return resp, err
+}
+
+// MergeAuthors moves every book of the source authors to the target, keeps the sources'
+// names as aliases of the target, deletes the sources and leaves redirects behind, all in
+// one transaction. The moved books, the target and every source get an audit entry.
+func (r *authorRepo) MergeAuthors(targetID string, sourceIDs []string, actor string) (models.AuthorMerge, error) {
+	resp := models.AuthorMerge{
+		TargetID:  targetID,
+		MergedIDs: sourceIDs,
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`SELECT count(1) FROM (
+		SELECT id FROM author WHERE id = $1 OR id = ANY($2) ORDER BY id FOR UPDATE
+	) a`, targetID, pq.Array(sourceIDs)).Scan(&locked)
+	if err != nil {
+		return resp, err
+	}
+
+	if locked != len(sourceIDs)+1 {
+		return resp, errors.New("there is no author with the given id")
+	}
+
+	targetBefore, err := lockedRow(tx, "author", targetID)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	return rowsAffected, err
+	sources := make([]map[string]interface{}, len(sourceIDs))
+	for i, sourceID := range sourceIDs {
+		if sources[i], err = lockedRow(tx, "author", sourceID); err != nil {
+			return resp, err
+		}
+	}
+
+	rows, err := tx.Query(`UPDATE book b SET author_id = $1, updated_at = now()
+		FROM book old
+		WHERE old.id = b.id AND b.author_id = ANY($2)
+		RETURNING b.id, old.author_id`, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return resp, err
+	}
+
+	moved := make(map[string]string)
+	for rows.Next() {
+		var bookID, authorID string
+		if err := rows.Scan(&bookID, &authorID); err != nil {
+			rows.Close()
+			return resp, err
+		}
+		moved[bookID] = authorID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return resp, err
+	}
+
+	resp.BooksMoved = int64(len(moved))
+
+	for bookID, authorID := range moved {
+		before := map[string]interface{}{"author_id": authorID}
+		after := map[string]interface{}{"author_id": targetID}
+		if err := recordChange(tx, "book", bookID, actor, models.AuditUpdate, before, after); err != nil {
+			return resp, err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE author t SET
+		aliases = ARRAY(
+			SELECT alias FROM (
+				SELECT unnest(t.aliases) AS alias
+				UNION
+				SELECT s.display_name FROM author s WHERE s.id = ANY($2)
+				UNION
+				SELECT unnest(s.aliases) FROM author s WHERE s.id = ANY($2)
+			) names
+			WHERE alias <> t.display_name
+			ORDER BY alias
+		),
+		updated_at = now()
+	WHERE t.id = $1`, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return resp, err
+	}
+
+	// Authors merged earlier into one of the sources now point straight at the target.
+	if _, err := tx.Exec(`UPDATE author_redirect SET to_id = $1 WHERE to_id = ANY($2)`, targetID, pq.Array(sourceIDs)); err != nil {
+		return resp, err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO author_redirect (from_id, to_id) SELECT unnest($2::uuid[]), $1::uuid`, targetID, pq.Array(sourceIDs)); err != nil {
+		return resp, err
+	}
+
+	// Old links by slug to a merged author lead to the target as well.
+	_, err = tx.Exec(`INSERT INTO slug_history (entity, slug, entity_id)
+		SELECT 'author', slug, $1::uuid FROM author WHERE id = ANY($2)
+		UNION
+		SELECT 'author', slug, $1::uuid FROM slug_history WHERE entity = 'author' AND entity_id = ANY($2)
+		ON CONFLICT (entity, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id`, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return resp, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM author WHERE id = ANY($1)`, pq.Array(sourceIDs)); err != nil {
+		return resp, err
+	}
+
+	targetAfter, err := lockedRow(tx, "author", targetID)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := recordChange(tx, "author", targetID, actor, models.AuditUpdate, targetBefore, targetAfter); err != nil {
+		return resp, err
+	}
+
+	for i, sourceID := range sourceIDs {
+		after := map[string]interface{}{"merged_into": targetID}
+		if err := recordChange(tx, "author", sourceID, actor, models.AuditMerge, sources[i], after); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, tx.Commit()
+}
+
+// GetSlugRedirect returns the current slug of the author that used slug before it was
+// renamed or merged.
+func (r *authorRepo) GetSlugRedirect(slug string) (string, error) {
+	return slugRedirect(r.db, "author", slug)
 }
@@ -1,20 +1,163 @@
 package postgres
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/saidakhmatov/catalog_of_books/models"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type bookRepo struct {
 	db *sqlx.DB
 }
 
-func (r *bookRepo) CreateBook(details models.Book) (string, error) {
+// copyAvailabilityJoin attaches the copy counts by status to every selected book row.
+const copyAvailabilityJoin = ` LEFT JOIN LATERAL (
+		SELECT
+			count(1) AS copies_total,
+			count(1) FILTER (WHERE status = 'available') AS copies_available,
+			count(1) FILTER (WHERE status = 'on_loan') AS copies_on_loan,
+			count(1) FILTER (WHERE status = 'on_hold') AS copies_on_hold,
+			count(1) FILTER (WHERE status = 'lost') AS copies_lost,
+			count(1) FILTER (WHERE status = 'withdrawn') AS copies_withdrawn
+		FROM copy
+		WHERE copy.book_id = book.id
+	) copies ON true`
+
+// bookSortColumns lists the columns the book list may be ordered by.
+var bookSortColumns = map[string]bool{
+	"book_name":        true,
+	"publication_year": true,
+	"average_rating":   true,
+	"rating_count":     true,
+	"created_at":       true,
+	"updated_at":       true,
+}
+
+// orderBy turns a sort parameter such as "-average_rating" into an ORDER BY clause,
+// rejecting columns that are not whitelisted.
+func orderBy(sort string, columns map[string]bool) (string, error) {
+	if len(sort) == 0 {
+		return "", nil
+	}
+
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	if !columns[sort] {
+		return "", fmt.Errorf("cannot sort by %q", sort)
+	}
+
+	return " ORDER BY " + sort + " " + direction + " NULLS LAST, id", nil
+}
+
+// bookFieldColumns lists the fields a book query can select, in select list order. The
+// availability counts come from copyAvailabilityJoin, which every book query includes.
+var bookFieldColumns = []fieldColumn{
+	{"id", []string{"id"}},
+	{"slug", []string{"slug"}},
+	{"book_name", []string{"book_name"}},
+	{"author_id", []string{"author_id"}},
+	{"category_id", []string{"category_id"}},
+	{"publication_year", []string{"publication_year"}},
+	{"language", []string{"language"}},
+	{"page_count", []string{"page_count"}},
+	{"description", []string{"description"}},
+	{"edition", []string{"edition"}},
+	{"format", []string{"format"}},
+	{"series_id", []string{"series_id"}},
+	{"series_position", []string{"series_position"}},
+	{"average_rating", []string{"average_rating"}},
+	{"rating_count", []string{"rating_count"}},
+	{"created_at", []string{"created_at"}},
+	{"updated_at", []string{"updated_at"}},
+	{"availability", []string{"copies_total", "copies_available", "copies_on_loan", "copies_on_hold", "copies_lost", "copies_withdrawn"}},
+	{"previous", nil},
+	{"next", nil},
+}
+
+// allBookFields and bookColumns select every field of a book.
+var allBookFields, bookColumns = allFields(bookFieldColumns)
+
+// bookHistoryFieldColumns are the fields kept in the history of a book. Copies and series
+// neighbours are not, so reads as of a past time leave them out.
+var bookHistoryFieldColumns = withoutFields(bookFieldColumns, "availability", "previous", "next")
+
+// bookListFieldColumns are the fields a list of books can select. The series neighbours
+// take a read per book, so only reads of single books and of books by id have them.
+var bookListFieldColumns = withoutFields(bookFieldColumns, "previous", "next")
+
+// rowScanner is satisfied by *sql.Row, *sql.Rows and their sqlx counterparts.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// bookSelect returns the columns and select list for the requested book fields. The
+// series links need the book's series and position, so those are selected with them.
+func bookSelect(fields []string) ([]fieldColumn, string, error) {
+	for _, field := range fields {
+		if field == "previous" || field == "next" {
+			fields = withFields(fields, "series_id", "series_position")
+			break
+		}
+	}
+
+	return selectFields(bookFieldColumns, fields)
+}
+
+func scanBook(row rowScanner, selected []fieldColumn) (models.Book, error) {
+	var resp models.Book
+	var availability models.CopyAvailability
+
+	err := scanFields(row, selected, map[string][]interface{}{
+		"id":               {&resp.ID},
+		"slug":             {&resp.Slug},
+		"book_name":        {&resp.BookName},
+		"author_id":        {&resp.AuthorID},
+		"category_id":      {&resp.CategoryID},
+		"publication_year": {&resp.PublicationYear},
+		"language":         {&resp.Language},
+		"page_count":       {&resp.PageCount},
+		"description":      {&resp.Description},
+		"edition":          {&resp.Edition},
+		"format":           {&resp.Format},
+		"series_id":        {&resp.SeriesID},
+		"series_position":  {&resp.SeriesPosition},
+		"average_rating":   {&resp.AverageRating},
+		"rating_count":     {&resp.RatingCount},
+		"created_at":       {&resp.CreatedAt},
+		"updated_at":       {&resp.UpdatedAt},
+		"availability": {
+			&availability.Total,
+			&availability.Available,
+			&availability.OnLoan,
+			&availability.OnHold,
+			&availability.Lost,
+			&availability.Withdrawn,
+		},
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	if hasField(selected, "availability") {
+		resp.Availability = &availability
+	}
+
+	return resp, nil
+}
+
+func (r *bookRepo) CreateBook(details models.Book, actor string) (models.Book, error) {
 	
-	var resp string
+	var resp models.Book
 	
 	var countCategory int
 	
@@ -42,11 +185,29 @@ func (r *bookRepo) CreateBook(details models.Book) (string, error) {
 		return resp, errors.New("there is no author with the given id")
 	}
 
-	query := `INSERT INTO book (
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	slug, err := uniqueSlug(tx, "book", details.BookName, details.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	query := `WITH inserted AS (INSERT INTO book (
 		id,
+		slug,
 		book_name,
 		category_id,
 		author_id,
+		publication_year,
+		language,
+		page_count,
+		description,
+		edition,
+		format,
 		created_at,
 		updated_at
 	) VALUES (
@@ -55,78 +216,296 @@ func (r *bookRepo) CreateBook(details models.Book) (string, error) {
 		$3,
 		$4,
 		$5,
-		$6
-	) RETURNING id;`
-
-	row := r.db.QueryRow(query,
+		$6,
+		$7,
+		$8,
+		$9,
+		$10,
+		$11,
+		$12,
+		$13
+	) RETURNING *)
+	SELECT` + bookColumns + `
+	FROM inserted AS book` + copyAvailabilityJoin
+
+	row := tx.QueryRow(query,
 		details.ID,
+		slug,
 		details.BookName,
 		details.CategoryID,
 		details.AuthorID,
+		details.PublicationYear,
+		details.Language,
+		details.PageCount,
+		details.Description,
+		details.Edition,
+		details.Format,
 		details.CreatedAt,
 		details.UpdatedAt,
 	)
 
-	if err := row.Scan(&resp); err != nil {
-		return "", err
+	resp, err = scanBook(row, allBookFields)
+	if err != nil {
+		return resp, err
 	}
 
-	return resp, nil
+	after, err := lockedRow(tx, "book", resp.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := recordChange(tx, "book", resp.ID, actor, models.AuditCreate, nil, after); err != nil {
+		return resp, err
+	}
+
+	return resp, tx.Commit()
 }
 
-func (r *bookRepo) GetBook(id string) (models.Book, error) {
+// GetBook returns the book with the given id or slug. When fields are given only those are
+// selected and filled in.
+func (r *bookRepo) GetBook(id string, fields ...string) (models.Book, error) {
+
+	selected, columns, err := bookSelect(fields)
+	if err != nil {
+		return models.Book{}, err
+	}
 	
-	var resp models.Book
-	query := `
-				SELECT
-					id,
-					book_name,
-					author_id,
-					category_id,
-					created_at,
-					updated_at
+	query := `SELECT` + columns + `
 				FROM
-					book
-				WHERE id=$1;
+					book` + copyAvailabilityJoin + `
+				WHERE ` + lookupColumn(id) + ` = $1;
 			`
 
-	row := r.db.QueryRow(query, id)
-	if err := row.Scan(
-		&resp.ID,
-		&resp.BookName,
-		&resp.AuthorID,
-		&resp.CategoryID,
-		&resp.CreatedAt,
-		&resp.UpdatedAt,
-	); err != nil {
+	resp, err := scanBook(r.db.QueryRow(query, id), selected)
+	if err != nil {
 		return resp, err
 	}
 
+	if !hasField(selected, "previous") && !hasField(selected, "next") {
+		return resp, nil
+	}
+
+	return resp, r.attachSeriesLinks(&resp)
+}
+
+// GetBookAsOf returns the book with the given id or slug as it was at asOf. When fields
+// are given only those are selected and filled in.
+func (r *bookRepo) GetBookAsOf(id string, asOf time.Time, fields ...string) (models.Book, error) {
+
+	selected, columns, err := selectFields(bookHistoryFieldColumns, fields)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	query := `SELECT` + columns + `
+				FROM ` + tableAsOf("book", "$2") + `
+				WHERE ` + lookupColumn(id) + ` = $1;
+			`
+
+	return scanBook(r.db.QueryRow(query, id, asOf), selected)
+}
+
+// attachSeriesLinks fills the previous and next books of a book that belongs to a series.
+func (r *bookRepo) attachSeriesLinks(book *models.Book) error {
+	if book.SeriesID == nil || book.SeriesPosition == nil {
+		return nil
+	}
+
+	previous, err := r.seriesNeighbour(*book.SeriesID, *book.SeriesPosition, false)
+	if err != nil {
+		return err
+	}
+	book.Previous = previous
+
+	next, err := r.seriesNeighbour(*book.SeriesID, *book.SeriesPosition, true)
+	if err != nil {
+		return err
+	}
+	book.Next = next
+
+	return nil
+}
+
+// seriesNeighbour returns the book right before or after position in the series, or nil if there is none.
+func (r *bookRepo) seriesNeighbour(seriesID string, position float64, after bool) (*models.BookSeriesLink, error) {
+	var resp models.BookSeriesLink
+
+	query := `SELECT id, book_name, series_position FROM book WHERE series_id = $1 AND series_position < $2 ORDER BY series_position DESC LIMIT 1;`
+	if after {
+		query = `SELECT id, book_name, series_position FROM book WHERE series_id = $1 AND series_position > $2 ORDER BY series_position ASC LIMIT 1;`
+	}
+
+	row := r.db.QueryRow(query, seriesID, position)
+
+	if err := row.Scan(&resp.ID, &resp.BookName, &resp.SeriesPosition); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetBooksByIDs returns the books with the given ids in the order the ids are given.
+// Ids without a book are left out. The id is always selected so callers can tell which.
+func (r *bookRepo) GetBooksByIDs(ids []string, fields ...string) ([]models.Book, error) {
+	var resp []models.Book = []models.Book{}
+
+	selected, columns, err := bookSelect(withFields(fields, "id"))
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT` + columns + `
+	FROM
+		book` + copyAvailabilityJoin + `
+	WHERE id = ANY($1::uuid[])
+	ORDER BY array_position($1::uuid[], id)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		book, err := scanBook(rows, selected)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !hasField(selected, "previous") && !hasField(selected, "next") {
+		return resp, nil
+	}
+
+	for i := range resp {
+		if err := r.attachSeriesLinks(&resp[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	return resp, nil
 }
 
-func (r *bookRepo) GetAllBooks(queryParam models.ApplicationQueryParamModel) ([]models.Book, error) {
+// GetBooksByAuthorIDs returns up to limit books of each of the given authors, by name.
+func (r *bookRepo) GetBooksByAuthorIDs(authorIDs []string, limit int, fields ...string) ([]models.Book, error) {
+	return r.getBooksGroupedBy("author_id", "book_name", authorIDs, limit, fields)
+}
+
+// GetBooksByCategoryIDs returns up to limit books of each of the given categories, by name.
+func (r *bookRepo) GetBooksByCategoryIDs(categoryIDs []string, limit int, fields ...string) ([]models.Book, error) {
+	return r.getBooksGroupedBy("category_id", "book_name", categoryIDs, limit, fields)
+}
+
+// GetBooksBySeriesIDs returns up to limit books of each of the given series, by position.
+func (r *bookRepo) GetBooksBySeriesIDs(seriesIDs []string, limit int, fields ...string) ([]models.Book, error) {
+	return r.getBooksGroupedBy("series_id", "series_position", seriesIDs, limit, fields)
+}
+
+// getBooksGroupedBy returns up to limit books for each of the ids in column, ordered by
+// order within each id, and the ids in the order given. column is always selected so
+// callers can group the books.
+func (r *bookRepo) getBooksGroupedBy(column, order string, ids []string, limit int, fields []string) ([]models.Book, error) {
+	var resp []models.Book = []models.Book{}
+
+	selected, columns, err := bookSelect(withFields(fields, "id", column))
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT` + columns + `
+	FROM (
+		SELECT *, row_number() OVER (PARTITION BY ` + column + ` ORDER BY ` + order + `, id) AS group_row
+		FROM book
+		WHERE ` + column + ` = ANY($1::uuid[])
+	) book` + copyAvailabilityJoin + `
+	WHERE group_row <= $2
+	ORDER BY array_position($1::uuid[], ` + column + `), group_row`
+
+	rows, err := r.db.Query(query, pq.Array(ids), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		book, err := scanBook(rows, selected)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !hasField(selected, "previous") && !hasField(selected, "next") {
+		return resp, nil
+	}
+
+	for i := range resp {
+		if err := r.attachSeriesLinks(&resp[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *bookRepo) GetAllBooks(queryParam models.BookQueryParamModel) ([]models.Book, error) {
 	
 	var resp []models.Book = []models.Book{}
 
 	params := make(map[string]interface{})
 
-	query := `SELECT
-		id,
-		category_id,
-		author_id,
-		book_name,
-		created_at,
-		updated_at
+	source, join := "book", copyAvailabilityJoin
+
+	selected, columns, err := selectFields(bookListFieldColumns, queryParam.Fields)
+	if !queryParam.AsOf.IsZero() {
+		params["as_of"] = queryParam.AsOf
+		source, join = tableAsOf("book", ":as_of"), ""
+		selected, columns, err = selectFields(bookHistoryFieldColumns, queryParam.Fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT` + columns + `
 	FROM
-		book`
+		` + source + join
 	filter := " WHERE 1=1"
 	offset := " OFFSET 0"
 	limit := " LIMIT 10"
 
 	if len(queryParam.Search) > 0 {
 		params["search"] = queryParam.Search
-		filter += " AND (name ILIKE '%' || :search || '%')"
+		filter += " AND (book_name ILIKE '%' || :search || '%')"
+	}
+
+	if len(queryParam.Language) > 0 {
+		params["language"] = queryParam.Language
+		filter += " AND language = :language"
+	}
+
+	if len(queryParam.Format) > 0 {
+		params["format"] = queryParam.Format
+		filter += " AND format = :format"
+	}
+
+	if queryParam.YearFrom > 0 {
+		params["year_from"] = queryParam.YearFrom
+		filter += " AND publication_year >= :year_from"
+	}
+
+	if queryParam.YearTo > 0 {
+		params["year_to"] = queryParam.YearTo
+		filter += " AND publication_year <= :year_to"
 	}
 
 	if queryParam.Offset > 0 {
@@ -139,14 +518,12 @@ func (r *bookRepo) GetAllBooks(queryParam models.ApplicationQueryParamModel) ([]
 		limit = " LIMIT :limit"
 	}
 
-	countQuery := "SELECT count(1) FROM book" + filter
-	row, err := r.db.NamedQuery(countQuery, params)
+	order, err := orderBy(queryParam.Sort, bookSortColumns)
 	if err != nil {
 		return nil, err
 	}
-	defer row.Close()
 
-	q := query + filter + offset + limit
+	q := query + filter + order + offset + limit
 	rows, err := r.db.NamedQuery(q, params)
 	
 	if err != nil {
@@ -155,16 +532,7 @@ func (r *bookRepo) GetAllBooks(queryParam models.ApplicationQueryParamModel) ([]
 	defer rows.Close()
 	
 	for rows.Next() {
-		var book models.Book
-		err = rows.Scan(
-			&book.ID,
-			&book.CategoryID,
-			&book.AuthorID,
-			&book.BookName,
-			&book.CreatedAt,
-			&book.UpdatedAt,
-		)
-
+		book, err := scanBook(rows, selected)
 		if err != nil {
 			return nil, err
 		}
@@ -174,60 +542,172 @@ func (r *bookRepo) GetAllBooks(queryParam models.ApplicationQueryParamModel) ([]
 	return resp, nil
 }
 
-func (r *bookRepo) UpdateBook(entity models.UpdateBook, id string) (int64, error) {
-	
-	params := make(map[string]interface{})
-	
-	params["id"] = id
+func (r *bookRepo) UpdateBook(entity models.UpdateBook, id, actor string) (models.Book, error) {
 
-	query := `UPDATE book SET `
+	changes := make(map[string]interface{})
 
 	if len(entity.CategoryID) > 0 {
-		params["category_id"] = entity.CategoryID
-		query += `category_id = :category_id,`
+		changes["category_id"] = entity.CategoryID
 	}
 
 	if len(entity.AuthorID) > 0 {
-		params["author_id"] = entity.AuthorID
-		query += `author_id = :author_id,`
+		changes["author_id"] = entity.AuthorID
 	}
 
 	if len(entity.BookName) > 0 {
-		params["book_name"] = entity.BookName
-		query += `book_name = :book_name,`
+		changes["book_name"] = entity.BookName
 	}
 
-	query += `updated_at =  now() WHERE id =:id`
+	if entity.PublicationYear != nil {
+		changes["publication_year"] = *entity.PublicationYear
+	}
+
+	if len(entity.Language) > 0 {
+		changes["language"] = entity.Language
+	}
 
-	result, err := r.db.NamedExec(query, params)
+	if entity.PageCount != nil {
+		changes["page_count"] = *entity.PageCount
+	}
+
+	if len(entity.Description) > 0 {
+		changes["description"] = entity.Description
+	}
+
+	if len(entity.Edition) > 0 {
+		changes["edition"] = entity.Edition
+	}
+
+	if len(entity.Format) > 0 {
+		changes["format"] = entity.Format
+	}
+
+	return r.updateBook(changes, id, actor, models.AuditUpdate)
+}
+
+// bookEditableColumns are the columns an update or a revert may set, in SET clause order.
+var bookEditableColumns = []string{
+	"category_id",
+	"author_id",
+	"book_name",
+	"publication_year",
+	"language",
+	"page_count",
+	"description",
+	"edition",
+	"format",
+}
+
+// updateBook sets the given editable columns of a book, checking that a new author and
+// category exist, renaming the slug with the title and recording the change as action.
+func (r *bookRepo) updateBook(changes map[string]interface{}, id, actor, action string) (models.Book, error) {
+
+	var resp models.Book
+	
+	params := make(map[string]interface{})
 	
+	params["id"] = id
+
+	query := `UPDATE book SET `
+
+	for _, column := range bookEditableColumns {
+		if value, ok := changes[column]; ok {
+			params[column] = value
+			query += column + ` = :` + column + `,`
+		}
+	}
+
+	query += `updated_at =  now() WHERE id =:id`
+
+	tx, err := r.db.Beginx()
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
-	
+	before, err := lockedRow(tx, "book", id)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	return rowsAffected, err
-}
+	if categoryID, ok := changes["category_id"]; ok {
+		var countCategory int
+		if err := tx.QueryRow(`SELECT count(1) FROM book_category WHERE id=$1;`, categoryID).Scan(&countCategory); err != nil {
+			return resp, err
+		}
+		if countCategory < 1 {
+			return resp, errors.New("there is no category_name with the given id")
+		}
+	}
 
-func (r *bookRepo) DeleteBook(id string) (int64, error) {
-	query := `DELETE FROM book WHERE id = $1`
+	if authorID, ok := changes["author_id"]; ok {
+		var countAuthor int
+		if err := tx.QueryRow(`SELECT count(1) FROM author WHERE id=$1;`, authorID).Scan(&countAuthor); err != nil {
+			return resp, err
+		}
+		if countAuthor < 1 {
+			return resp, errors.New("there is no author with the given id")
+		}
+	}
 
-	result, err := r.db.Exec(query, id)
-	
+	if bookName, ok := changes["book_name"].(string); ok && len(bookName) > 0 {
+		if err := renameSlug(tx, "book", bookName, id); err != nil {
+			return resp, err
+		}
+	}
+
+	q, args, err := sqlx.Named(`WITH updated AS (`+query+` RETURNING *)
+		SELECT`+bookColumns+`
+		FROM updated AS book`+copyAvailabilityJoin, params)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	
+	resp, err = scanBook(tx.QueryRowx(tx.Rebind(q), args...), allBookFields)
+	if err != nil {
+		return resp, err
+	}
+
+	after, err := lockedRow(tx, "book", id)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := recordChange(tx, "book", id, actor, action, before, after); err != nil {
+		return resp, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return resp, err
+	}
+
+	return resp, r.attachSeriesLinks(&resp)
+}
+
+// RevertBook restores the editable columns of a book to how they were at the given
+// version of its history, through the same path as an update. The revert is recorded
+// as a new version. A version that does not exist is sql.ErrNoRows.
+func (r *bookRepo) RevertBook(id string, version int, actor string) (models.Book, error) {
+	snapshot, err := snapshotAt(r.db, "book", id, version)
 	if err != nil {
-		return 0, err
+		return models.Book{}, err
 	}
 
-	return rowsAffected, err
+	changes := make(map[string]interface{})
+	for _, column := range bookEditableColumns {
+		if value, ok := snapshot[column]; ok {
+			changes[column] = value
+		}
+	}
+
+	return r.updateBook(changes, id, actor, models.AuditRevert)
+}
+
+func (r *bookRepo) DeleteBook(id, actor string) (int64, error) {
+	return deleteAudited(r.db, "book", id, actor)
+}
+
+// GetSlugRedirect returns the current slug of the book that used slug before it was renamed.
+func (r *bookRepo) GetSlugRedirect(slug string) (string, error) {
+	return slugRedirect(r.db, "book", slug)
 }
@@ -1,57 +1,146 @@
 package postgres
 
 import (
+	"time"
+
 	"github.com/saidakhmatov/catalog_of_books/models"
 	
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type bookCategoryRepo struct {
 	db *sqlx.DB
 }
 
-func (r *bookCategoryRepo) CreateBookCategory(entity models.BookCategory) (string, error) {
-	var resp string
+// bookCategoryFieldColumns lists the fields a category query can select, in select list order.
+var bookCategoryFieldColumns = []fieldColumn{
+	{"id", []string{"id"}},
+	{"slug", []string{"slug"}},
+	{"category_name", []string{"category_name"}},
+	{"created_at", []string{"created_at"}},
+	{"updated_at", []string{"updated_at"}},
+}
+
+// allBookCategoryFields and bookCategoryColumns select every field of a category.
+var allBookCategoryFields, bookCategoryColumns = allFields(bookCategoryFieldColumns)
+
+func scanBookCategory(row rowScanner, selected []fieldColumn) (models.BookCategory, error) {
+	var resp models.BookCategory
+
+	err := scanFields(row, selected, map[string][]interface{}{
+		"id":            {&resp.ID},
+		"slug":          {&resp.Slug},
+		"category_name": {&resp.CategoryName},
+		"created_at":    {&resp.CreatedAt},
+		"updated_at":    {&resp.UpdatedAt},
+	})
 
-	query := `INSERT INTO book_category (id, category_name, created_at, updated_at) VALUES ($1, $2,	$3,	$4) RETURNING id;`
+	return resp, err
+}
 
-	row := r.db.QueryRow(query, entity.ID, entity.CategoryName, entity.CreatedAt, entity.UpdatedAt)
+func (r *bookCategoryRepo) CreateBookCategory(entity models.BookCategory, actor string) (models.BookCategory, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return models.BookCategory{}, err
+	}
+	defer tx.Rollback()
 
-	if err := row.Scan(&resp); err != nil {
-		return "", err
+	slug, err := uniqueSlug(tx, "book_category", entity.CategoryName, entity.ID)
+	if err != nil {
+		return models.BookCategory{}, err
 	}
 
-	return resp, nil
+	query := `INSERT INTO book_category (id, slug, category_name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING` + bookCategoryColumns
+
+	row := tx.QueryRow(query, entity.ID, slug, entity.CategoryName, entity.CreatedAt, entity.UpdatedAt)
+
+	resp, err := scanBookCategory(row, allBookCategoryFields)
+	if err != nil {
+		return resp, err
+	}
+
+	after, err := lockedRow(tx, "book_category", resp.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := recordChange(tx, "book_category", resp.ID, actor, models.AuditCreate, nil, after); err != nil {
+		return resp, err
+	}
+
+	return resp, tx.Commit()
 }
 
-func (r *bookCategoryRepo) GetBookCategory(id string) (models.BookCategory, error) {
-	
-	var resp models.BookCategory
+// GetBookCategory returns the category with the given id or slug. When fields are given
+// only those are selected and filled in.
+func (r *bookCategoryRepo) GetBookCategory(id string, fields ...string) (models.BookCategory, error) {
+
+	selected, columns, err := selectFields(bookCategoryFieldColumns, fields)
+	if err != nil {
+		return models.BookCategory{}, err
+	}
 	
 	query := `
-				SELECT
-					id,
-					category_name,
-					created_at,
-					updated_at
+				SELECT` + columns + `
 				FROM
 					book_category
-				WHERE id=$1;
+				WHERE ` + lookupColumn(id) + ` = $1;
 			`
 
-	row := r.db.QueryRow(query, id)
-	
-	if err := row.Scan(
-		&resp.ID,
-		&resp.CategoryName,
-		&resp.CreatedAt,
-		&resp.UpdatedAt,
-	); err != nil {
+	return scanBookCategory(r.db.QueryRow(query, id), selected)
+}
+
+// GetBookCategoriesByIDs returns the categories with the given ids in the order the ids
+// are given. Ids without a category are left out. The id is always selected so callers
+// can tell which.
+func (r *bookCategoryRepo) GetBookCategoriesByIDs(ids []string, fields ...string) ([]models.BookCategory, error) {
+	var resp []models.BookCategory = []models.BookCategory{}
+
+	selected, columns, err := selectFields(bookCategoryFieldColumns, withFields(fields, "id"))
+	if err != nil {
 		return resp, err
 	}
 
-	return resp, nil
+	query := `SELECT` + columns + `
+	FROM
+		book_category
+	WHERE id = ANY($1::uuid[])
+	ORDER BY array_position($1::uuid[], id)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		category, err := scanBookCategory(rows, selected)
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, category)
+	}
+
+	return resp, rows.Err()
+}
+
+// GetBookCategoryAsOf returns the category with the given id or slug as it was at asOf.
+// When fields are given only those are selected and filled in.
+func (r *bookCategoryRepo) GetBookCategoryAsOf(id string, asOf time.Time, fields ...string) (models.BookCategory, error) {
+
+	selected, columns, err := selectFields(bookCategoryFieldColumns, fields)
+	if err != nil {
+		return models.BookCategory{}, err
+	}
+
+	query := `
+				SELECT` + columns + `
+				FROM ` + tableAsOf("book_category", "$2") + `
+				WHERE ` + lookupColumn(id) + ` = $1;
+			`
+
+	return scanBookCategory(r.db.QueryRow(query, id, asOf), selected)
 }
 
 func (r *bookCategoryRepo) GetAllBookCategories(queryParam models.ApplicationQueryParamModel) ([]models.BookCategory, error) {
@@ -60,13 +149,20 @@ func (r *bookCategoryRepo) GetAllBookCategories(queryParam models.ApplicationQue
 
 	params := make(map[string]interface{})
 
-	query := `SELECT
-		id,
-		category_name,
-		created_at,
-		updated_at
+	selected, columns, err := selectFields(bookCategoryFieldColumns, queryParam.Fields)
+	if err != nil {
+		return resp, err
+	}
+
+	source := "book_category"
+	if !queryParam.AsOf.IsZero() {
+		params["as_of"] = queryParam.AsOf
+		source = tableAsOf("book_category", ":as_of")
+	}
+
+	query := `SELECT` + columns + `
 	FROM
-		book_category`
+		` + source
 	filter := " WHERE 1=1"
 	offset := " OFFSET 0"
 	limit := " LIMIT 10"
@@ -86,7 +182,7 @@ func (r *bookCategoryRepo) GetAllBookCategories(queryParam models.ApplicationQue
 		limit = " LIMIT :limit"
 	}
 
-	countQuery := "SELECT count(1) FROM book_category" + filter
+	countQuery := "SELECT count(1) FROM " + source + filter
 	row, err := r.db.NamedQuery(countQuery, params)
 	if err != nil {
 		return resp, err
@@ -102,14 +198,7 @@ func (r *bookCategoryRepo) GetAllBookCategories(queryParam models.ApplicationQue
 	defer rows.Close()
 	
 	for rows.Next() {
-		var category models.BookCategory
-		err = rows.Scan(
-			&category.ID,
-			&category.CategoryName,
-			&category.CreatedAt,
-			&category.UpdatedAt,
-		)
-
+		category, err := scanBookCategory(rows, selected)
 		if err != nil {
 			return resp, err
 		}
@@ -119,7 +208,9 @@ func (r *bookCategoryRepo) GetAllBookCategories(queryParam models.ApplicationQue
 	return resp, nil
 }
 
-func (r *bookCategoryRepo) UpdateBookCategory(entity *models.UpdateBookCategory, id string) (int64, error) {
+func (r *bookCategoryRepo) UpdateBookCategory(entity *models.UpdateBookCategory, id, actor string) (models.BookCategory, error) {
+
+	var resp models.BookCategory
 	
 	params := make(map[string]interface{})
 	
@@ -133,38 +224,52 @@ func (r *bookCategoryRepo) UpdateBookCategory(entity *models.UpdateBookCategory,
 	}
 
 
-	query += `updated_at = now() WHERE id =:id`
+	query += `updated_at = now() WHERE id =:id RETURNING` + bookCategoryColumns
 
-	respult, err := r.db.NamedExec(query, params)
-	
+	tx, err := r.db.Beginx()
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := respult.RowsAffected()
-	
+	before, err := lockedRow(tx, "book_category", id)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	return rowsAffected, err
-}
+	if len(entity.CategoryName) > 0 {
+		if err := renameSlug(tx, "book_category", entity.CategoryName, id); err != nil {
+			return resp, err
+		}
+	}
 
-func (r *bookCategoryRepo) DeleteBookCategory(id string) (int64, error) {
-	
-	query := `DELETE FROM book_category WHERE id = $1`
+	q, args, err := sqlx.Named(query, params)
+	if err != nil {
+		return resp, err
+	}
 
-	respult, err := r.db.Exec(query, id)
-	
+	resp, err = scanBookCategory(tx.QueryRowx(tx.Rebind(q), args...), allBookCategoryFields)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	rowsAffected, err := respult.RowsAffected()
-	
+	after, err := lockedRow(tx, "book_category", id)
 	if err != nil {
-		return 0, err
+		return resp, err
 	}
 
-	return rowsAffected, err
+	if err := recordChange(tx, "book_category", id, actor, models.AuditUpdate, before, after); err != nil {
+		return resp, err
+	}
+
+	return resp, tx.Commit()
+}
+
+func (r *bookCategoryRepo) DeleteBookCategory(id, actor string) (int64, error) {
+	return deleteAudited(r.db, "book_category", id, actor)
+}
+
+// GetSlugRedirect returns the current slug of the category that used slug before it was renamed.
+func (r *bookCategoryRepo) GetSlugRedirect(slug string) (string, error) {
+	return slugRedirect(r.db, "book_category", slug)
 }
@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type copyRepo struct {
+	db *sqlx.DB
+}
+
+// CreateCopy adds a copy of a book. A copy added as available goes to the next hold in
+// the queue of its book, if anybody is waiting.
+func (r *copyRepo) CreateCopy(entity models.Copy, holdPickupPeriod time.Duration) (string, error) {
+	var resp string
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	var countBook int
+
+	q1 := `SELECT count(1) FROM book WHERE id=$1;`
+	row1 := tx.QueryRow(q1, entity.BookID)
+
+	if err := row1.Scan(&countBook); err != nil {
+		return resp, err
+	}
+
+	if countBook < 1 {
+		return resp, errors.New("there is no book with the given id")
+	}
+
+	query := `INSERT INTO copy (
+		id,
+		book_id,
+		barcode,
+		condition,
+		shelf_location,
+		acquisition_date,
+		status,
+		created_at,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		NULLIF($6, '')::date,
+		$7,
+		$8,
+		$9
+	) RETURNING id;`
+
+	row := tx.QueryRow(query,
+		entity.ID,
+		entity.BookID,
+		entity.Barcode,
+		entity.Condition,
+		entity.ShelfLocation,
+		entity.AcquisitionDate,
+		entity.Status,
+		entity.CreatedAt,
+		entity.UpdatedAt,
+	)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	if entity.Status == models.CopyStatusAvailable {
+		if err := promoteNextHold(tx, resp, holdPickupPeriod); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (r *copyRepo) GetCopy(bookID, id string) (models.Copy, error) {
+	var resp models.Copy
+
+	query := `
+				SELECT
+					id,
+					book_id,
+					barcode,
+					condition,
+					shelf_location,
+					COALESCE(to_char(acquisition_date, 'YYYY-MM-DD'), ''),
+					status,
+					created_at,
+					updated_at
+				FROM
+					copy
+				WHERE id=$1 AND book_id=$2;
+			`
+
+	row := r.db.QueryRow(query, id, bookID)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.BookID,
+		&resp.Barcode,
+		&resp.Condition,
+		&resp.ShelfLocation,
+		&resp.AcquisitionDate,
+		&resp.Status,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *copyRepo) GetCopyByBarcode(barcode string) (models.Copy, error) {
+	var resp models.Copy
+
+	query := `
+				SELECT
+					id,
+					book_id,
+					barcode,
+					condition,
+					shelf_location,
+					COALESCE(to_char(acquisition_date, 'YYYY-MM-DD'), ''),
+					status,
+					created_at,
+					updated_at
+				FROM
+					copy
+				WHERE barcode=$1;
+			`
+
+	row := r.db.QueryRow(query, barcode)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.BookID,
+		&resp.Barcode,
+		&resp.Condition,
+		&resp.ShelfLocation,
+		&resp.AcquisitionDate,
+		&resp.Status,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *copyRepo) GetAllCopies(bookID string, queryParam models.ApplicationQueryParamModel) ([]models.Copy, error) {
+	var resp []models.Copy = []models.Copy{}
+
+	params := make(map[string]interface{})
+
+	params["book_id"] = bookID
+
+	query := `SELECT
+		id,
+		book_id,
+		barcode,
+		condition,
+		shelf_location,
+		COALESCE(to_char(acquisition_date, 'YYYY-MM-DD'), ''),
+		status,
+		created_at,
+		updated_at
+	FROM
+		copy`
+	filter := " WHERE book_id = :book_id"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.Search) > 0 {
+		params["search"] = queryParam.Search
+		filter += " AND (barcode ILIKE '%' || :search || '%' OR shelf_location ILIKE '%' || :search || '%')"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	q := query + filter + " ORDER BY barcode" + offset + limit
+	rows, err := r.db.NamedQuery(q, params)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookCopy models.Copy
+		err = rows.Scan(
+			&bookCopy.ID,
+			&bookCopy.BookID,
+			&bookCopy.Barcode,
+			&bookCopy.Condition,
+			&bookCopy.ShelfLocation,
+			&bookCopy.AcquisitionDate,
+			&bookCopy.Status,
+			&bookCopy.CreatedAt,
+			&bookCopy.UpdatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, bookCopy)
+	}
+
+	return resp, nil
+}
+
+// UpdateCopy changes a copy of a book. A copy that becomes available again goes to the next
+// hold in the queue of its book, if anybody is waiting.
+func (r *copyRepo) UpdateCopy(entity models.UpdateCopy, bookID, id string, holdPickupPeriod time.Duration) (int64, error) {
+	params := make(map[string]interface{})
+
+	params["id"] = id
+	params["book_id"] = bookID
+
+	query := `UPDATE copy SET `
+
+	if len(entity.Barcode) > 0 {
+		params["barcode"] = entity.Barcode
+		query += `barcode = :barcode,`
+	}
+
+	if len(entity.Condition) > 0 {
+		params["condition"] = entity.Condition
+		query += `condition = :condition,`
+	}
+
+	if len(entity.ShelfLocation) > 0 {
+		params["shelf_location"] = entity.ShelfLocation
+		query += `shelf_location = :shelf_location,`
+	}
+
+	if len(entity.AcquisitionDate) > 0 {
+		params["acquisition_date"] = entity.AcquisitionDate
+		query += `acquisition_date = CAST(:acquisition_date AS date),`
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var status string
+
+	if len(entity.Status) > 0 {
+		// The lock holds off loans and returns of the copy until its status has changed.
+		row := tx.QueryRow(`SELECT status FROM copy WHERE id=$1 AND book_id=$2 FOR UPDATE;`, id, bookID)
+
+		if err := row.Scan(&status); err != nil {
+			return 0, err
+		}
+
+		if status == models.CopyStatusOnLoan {
+			return 0, errors.New("copy is on loan and must be returned before its status can change")
+		}
+
+		if status == models.CopyStatusOnHold {
+			return 0, errors.New("copy is held for a member and the hold must be cancelled before its status can change")
+		}
+
+		params["status"] = entity.Status
+		query += `status = :status,`
+	}
+
+	query += `updated_at = now() WHERE id =:id AND book_id =:book_id`
+
+	result, err := tx.NamedExec(query, params)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if entity.Status == models.CopyStatusAvailable && status != models.CopyStatusAvailable {
+		if err := promoteNextHold(tx, id, holdPickupPeriod); err != nil {
+			return 0, err
+		}
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+func (r *copyRepo) DeleteCopy(bookID, id string) (int64, error) {
+	query := `DELETE FROM copy WHERE id = $1 AND book_id = $2`
+
+	result, err := r.db.Exec(query, id, bookID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, err
+}
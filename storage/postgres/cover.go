@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type coverRepo struct {
+	db *sqlx.DB
+}
+
+func (r *coverRepo) GetCover(bookID string) (models.Cover, error) {
+	var resp models.Cover
+
+	query := `
+				SELECT
+					book_id,
+					content_type,
+					etag,
+					size,
+					width,
+					height,
+					updated_at
+				FROM
+					book_cover
+				WHERE book_id=$1;
+			`
+
+	row := r.db.QueryRow(query, bookID)
+
+	if err := row.Scan(
+		&resp.BookID,
+		&resp.ContentType,
+		&resp.ETag,
+		&resp.Size,
+		&resp.Width,
+		&resp.Height,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *coverRepo) UpsertCover(details models.Cover) error {
+	query := `INSERT INTO book_cover (
+		book_id,
+		content_type,
+		etag,
+		size,
+		width,
+		height,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7
+	) ON CONFLICT (book_id) DO UPDATE SET
+		content_type = EXCLUDED.content_type,
+		etag = EXCLUDED.etag,
+		size = EXCLUDED.size,
+		width = EXCLUDED.width,
+		height = EXCLUDED.height,
+		updated_at = EXCLUDED.updated_at;`
+
+	_, err := r.db.Exec(query,
+		details.BookID,
+		details.ContentType,
+		details.ETag,
+		details.Size,
+		details.Width,
+		details.Height,
+		details.UpdatedAt,
+	)
+
+	return err
+}
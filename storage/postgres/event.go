@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+type eventRepo struct {
+	db *sqlx.DB
+}
+
+// GetEvents returns up to limit events after the event afterID, oldest first, only of the
+// given entities unless none are given.
+func (r *eventRepo) GetEvents(afterID int64, entities []string, limit int) ([]models.StoredEvent, error) {
+	var resp []models.StoredEvent
+
+	rows, err := r.db.Query(`SELECT id, event_type, entity, payload
+		FROM outbox
+		WHERE id > $1 AND (COALESCE(cardinality($2::text[]), 0) = 0 OR entity = ANY($2))
+		ORDER BY id
+		LIMIT $3`, afterID, pq.Array(entities), limit)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event models.StoredEvent
+		if err := rows.Scan(&event.ID, &event.Type, &event.Entity, &event.Payload); err != nil {
+			return resp, err
+		}
+		resp = append(resp, event)
+	}
+
+	return resp, rows.Err()
+}
+
+// LatestEventID returns the id of the newest event, or 0 if there is none.
+func (r *eventRepo) LatestEventID() (int64, error) {
+	var resp int64
+
+	err := r.db.QueryRow(`SELECT COALESCE(max(id), 0) FROM outbox`).Scan(&resp)
+
+	return resp, err
+}
+
+// GetChangedEntities returns up to limit entities changed after the event afterID, each
+// once, ordered by their latest change. Reading again after the Seq of the last one
+// returns the entities changed since, including those already returned.
+func (r *eventRepo) GetChangedEntities(afterID int64, limit int) ([]models.EntityChange, error) {
+	var resp []models.EntityChange
+
+	rows, err := r.db.Query(`SELECT max(id) AS seq, entity, entity_id
+		FROM outbox
+		WHERE id > $1
+		GROUP BY entity, entity_id
+		ORDER BY seq
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change models.EntityChange
+		if err := rows.Scan(&change.Seq, &change.Entity, &change.EntityID); err != nil {
+			return resp, err
+		}
+		resp = append(resp, change)
+	}
+
+	return resp, rows.Err()
+}
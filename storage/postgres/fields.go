@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saidakhmatov/catalog_of_books/helper"
+)
+
+// fieldColumn maps a field name accepted by the fields parameter to the SQL expressions it
+// is selected with. Fields that are filled in after the query have no expressions.
+type fieldColumn struct {
+	name  string
+	exprs []string
+}
+
+// selectFields returns the columns of the requested fields, in the order they appear in
+// columns, together with their select list. Requesting no fields selects all of them.
+// Unknown field names are an error, so columns doubles as the whitelist of the entity.
+func selectFields(columns []fieldColumn, fields []string) ([]fieldColumn, string, error) {
+	wanted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !hasField(columns, field) {
+			names := make([]string, len(columns))
+			for i, column := range columns {
+				names[i] = column.name
+			}
+			return nil, "", fmt.Errorf("unknown field %q, must be one of %s", field, strings.Join(names, ", "))
+		}
+		wanted[field] = true
+	}
+
+	var selected []fieldColumn
+	var exprs []string
+	for _, column := range columns {
+		if len(fields) > 0 && !wanted[column.name] {
+			continue
+		}
+		selected = append(selected, column)
+		exprs = append(exprs, column.exprs...)
+	}
+
+	return selected, "\n\t\t" + strings.Join(exprs, ",\n\t\t"), nil
+}
+
+// allFields selects every field of columns.
+func allFields(columns []fieldColumn) ([]fieldColumn, string) {
+	selected, list, _ := selectFields(columns, nil)
+	return selected, list
+}
+
+// scanFields scans row into the destinations of the selected fields.
+func scanFields(row rowScanner, selected []fieldColumn, dests map[string][]interface{}) error {
+	var targets []interface{}
+	for _, column := range selected {
+		if len(column.exprs) == 0 {
+			continue
+		}
+		targets = append(targets, dests[column.name]...)
+	}
+
+	return row.Scan(targets...)
+}
+
+// withoutFields returns columns without the named fields.
+func withoutFields(columns []fieldColumn, names ...string) []fieldColumn {
+	var resp []fieldColumn
+	for _, column := range columns {
+		if !helper.Contains(names, column.name) {
+			resp = append(resp, column)
+		}
+	}
+	return resp
+}
+
+func hasField(columns []fieldColumn, name string) bool {
+	for _, column := range columns {
+		if column.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withFields adds extra to fields unless every field is requested anyway.
+func withFields(fields []string, extra ...string) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	resp := append([]string{}, fields...)
+	for _, field := range extra {
+		found := false
+		for _, f := range resp {
+			if f == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp = append(resp, field)
+		}
+	}
+	return resp
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type fineRepo struct {
+	db *sqlx.DB
+}
+
+// memberBalance sums a member's fine ledger: charges minus payments and waivers.
+func memberBalance(q sqlx.Queryer, memberID string) (int64, error) {
+	var balance int64
+
+	query := `SELECT COALESCE(sum(CASE WHEN kind = 'charge' THEN amount ELSE -amount END), 0) FROM fine WHERE member_id = $1;`
+
+	if err := q.QueryRowx(query, memberID).Scan(&balance); err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// CreateFine records a payment or waiver. The member row is locked so two concurrent
+// payments cannot together bring the balance below zero.
+func (r *fineRepo) CreateFine(details models.Fine) (string, error) {
+	var resp string
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	var countMember int
+
+	if err := tx.QueryRow(`SELECT count(1) FROM (SELECT id FROM member WHERE id=$1 FOR UPDATE) m;`, details.MemberID).Scan(&countMember); err != nil {
+		return resp, err
+	}
+
+	if countMember < 1 {
+		return resp, errors.New("there is no member with the given id")
+	}
+
+	if details.Kind != models.FineKindCharge {
+		balance, err := memberBalance(tx, details.MemberID)
+		if err != nil {
+			return resp, err
+		}
+
+		if details.Amount > balance {
+			return resp, errors.New("amount exceeds the outstanding balance")
+		}
+	}
+
+	query := `INSERT INTO fine (
+		id,
+		member_id,
+		loan_id,
+		kind,
+		amount,
+		note,
+		created_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7
+	) RETURNING id;`
+
+	row := tx.QueryRow(query,
+		details.ID,
+		details.MemberID,
+		details.LoanID,
+		details.Kind,
+		details.Amount,
+		details.Note,
+		details.CreatedAt,
+	)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (r *fineRepo) GetMemberFines(memberID string, queryParam models.ApplicationQueryParamModel) (models.FineAccount, error) {
+	resp := models.FineAccount{
+		MemberID: memberID,
+		Entries:  []models.Fine{},
+	}
+
+	balance, err := memberBalance(r.db, memberID)
+	if err != nil {
+		return resp, err
+	}
+	resp.Balance = balance
+
+	params := make(map[string]interface{})
+
+	params["member_id"] = memberID
+
+	query := `SELECT
+		id,
+		member_id,
+		loan_id,
+		kind,
+		amount,
+		note,
+		created_at
+	FROM
+		fine`
+	filter := " WHERE member_id = :member_id"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	q := query + filter + " ORDER BY created_at DESC, id" + offset + limit
+	rows, err := r.db.NamedQuery(q, params)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fine models.Fine
+		err = rows.Scan(
+			&fine.ID,
+			&fine.MemberID,
+			&fine.LoanID,
+			&fine.Kind,
+			&fine.Amount,
+			&fine.Note,
+			&fine.CreatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp.Entries = append(resp.Entries, fine)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,403 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type holdRepo struct {
+	db *sqlx.DB
+}
+
+// holdQueuePosition numbers the waiting holds of a title in the order they were placed.
+const holdQueuePosition = `CASE WHEN hold.status = 'waiting' THEN (
+			SELECT count(1) FROM hold queued
+			WHERE queued.book_id = hold.book_id
+				AND queued.status = 'waiting'
+				AND (queued.created_at, queued.id) <= (hold.created_at, hold.id)
+		) END`
+
+// promoteNextHold hands a copy that has just come back to the oldest waiting hold on its
+// title, or puts it back on the shelf when nobody is queueing. Locked holds are skipped so
+// concurrent returns of the same title each serve a different member. The copy is locked
+// first, so a hold being placed on the title either sees it available or is seen here.
+func promoteNextHold(tx *sqlx.Tx, copyID string, pickupPeriod time.Duration) error {
+	var bookID string
+
+	if err := tx.QueryRow(`SELECT book_id FROM copy WHERE id=$1 FOR UPDATE;`, copyID).Scan(&bookID); err != nil {
+		return err
+	}
+
+	var holdID string
+
+	query := `SELECT id FROM hold
+	WHERE book_id = $1 AND status = 'waiting'
+	ORDER BY created_at, id
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED;`
+
+	err := tx.QueryRow(query, bookID).Scan(&holdID)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = tx.Exec(`UPDATE copy SET status = $1, updated_at = now() WHERE id = $2`, models.CopyStatusAvailable, copyID)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE hold SET status = $1, copy_id = $2, pickup_expires_at = $3, updated_at = now() WHERE id = $4`,
+		models.HoldStatusReady, copyID, time.Now().Add(pickupPeriod), holdID,
+	); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE copy SET status = $1, updated_at = now() WHERE id = $2`, models.CopyStatusOnHold, copyID)
+	return err
+}
+
+func (r *holdRepo) CreateHold(details models.Hold) (string, error) {
+	var resp string
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	var countBook int
+
+	if err := tx.QueryRow(`SELECT count(1) FROM book WHERE id=$1;`, details.BookID).Scan(&countBook); err != nil {
+		return resp, err
+	}
+
+	if countBook < 1 {
+		return resp, errors.New("there is no book with the given id")
+	}
+
+	// Locking every copy of the title keeps returns from putting one back on the shelf
+	// until the hold is queued, where they will find it.
+	rows, err := tx.Query(`SELECT status FROM copy WHERE book_id=$1 ORDER BY id FOR UPDATE;`, details.BookID)
+	if err != nil {
+		return resp, err
+	}
+
+	var countAvailable int
+
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			rows.Close()
+			return resp, err
+		}
+		if status == models.CopyStatusAvailable {
+			countAvailable++
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return resp, err
+	}
+
+	if countAvailable > 0 {
+		return resp, errors.New("a copy of this book is available, check it out instead")
+	}
+
+	// Copies before the member, in the order CreateLoan locks them.
+	if err := tx.QueryRow(`SELECT id FROM member WHERE id=$1 FOR UPDATE;`, details.MemberID).Scan(new(string)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, errors.New("there is no member with the given id")
+		}
+		return resp, err
+	}
+
+	var countActive int
+
+	if err := tx.QueryRow(
+		`SELECT count(1) FROM hold WHERE member_id=$1 AND book_id=$2 AND status IN ('waiting', 'ready');`,
+		details.MemberID, details.BookID,
+	).Scan(&countActive); err != nil {
+		return resp, err
+	}
+
+	if countActive > 0 {
+		return resp, errors.New("member already has an active hold on this book")
+	}
+
+	query := `INSERT INTO hold (
+		id,
+		book_id,
+		member_id,
+		status,
+		created_at,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6
+	) RETURNING id;`
+
+	row := tx.QueryRow(query,
+		details.ID,
+		details.BookID,
+		details.MemberID,
+		details.Status,
+		details.CreatedAt,
+		details.UpdatedAt,
+	)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	return resp, tx.Commit()
+}
+
+func (r *holdRepo) GetHold(id string) (models.Hold, error) {
+	var resp models.Hold
+
+	query := `
+				SELECT
+					id,
+					book_id,
+					member_id,
+					copy_id,
+					status,
+					` + holdQueuePosition + `,
+					pickup_expires_at,
+					created_at,
+					updated_at
+				FROM
+					hold
+				WHERE id=$1;
+			`
+
+	row := r.db.QueryRow(query, id)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.BookID,
+		&resp.MemberID,
+		&resp.CopyID,
+		&resp.Status,
+		&resp.QueuePosition,
+		&resp.PickupExpiresAt,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GetBookHolds lists the active holds on a title: those ready for pickup first, then the queue.
+func (r *holdRepo) GetBookHolds(bookID string) ([]models.Hold, error) {
+	query := `SELECT
+		id,
+		book_id,
+		member_id,
+		copy_id,
+		status,
+		` + holdQueuePosition + `,
+		pickup_expires_at,
+		created_at,
+		updated_at
+	FROM
+		hold
+	WHERE book_id = $1 AND status IN ('waiting', 'ready')
+	ORDER BY status = 'waiting', created_at, id`
+
+	return r.queryHolds(query, bookID)
+}
+
+// GetMemberHolds lists every hold a member has placed, newest first.
+func (r *holdRepo) GetMemberHolds(memberID string) ([]models.Hold, error) {
+	query := `SELECT
+		id,
+		book_id,
+		member_id,
+		copy_id,
+		status,
+		` + holdQueuePosition + `,
+		pickup_expires_at,
+		created_at,
+		updated_at
+	FROM
+		hold
+	WHERE member_id = $1
+	ORDER BY created_at DESC, id`
+
+	return r.queryHolds(query, memberID)
+}
+
+func (r *holdRepo) queryHolds(query string, args ...interface{}) ([]models.Hold, error) {
+	var resp []models.Hold = []models.Hold{}
+
+	rows, err := r.db.Query(query, args...)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hold models.Hold
+		err = rows.Scan(
+			&hold.ID,
+			&hold.BookID,
+			&hold.MemberID,
+			&hold.CopyID,
+			&hold.Status,
+			&hold.QueuePosition,
+			&hold.PickupExpiresAt,
+			&hold.CreatedAt,
+			&hold.UpdatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, hold)
+	}
+
+	return resp, rows.Err()
+}
+
+// errHoldAssigned is returned when a hold is assigned a copy while it is being cancelled.
+var errHoldAssigned = errors.New("hold was assigned a copy while being cancelled")
+
+// CancelHold withdraws a hold. A copy that was already waiting for the member moves on to
+// the next hold in the queue.
+func (r *holdRepo) CancelHold(id string, pickupPeriod time.Duration) (int64, error) {
+	// A hold is assigned a copy at most once, so the second attempt finds it settled.
+	for {
+		rowsAffected, err := r.cancelHold(id, pickupPeriod)
+		if !errors.Is(err, errHoldAssigned) {
+			return rowsAffected, err
+		}
+	}
+}
+
+// cancelHold locks the copy of the hold before the hold itself, in the order CreateLoan
+// and promoteNextHold lock them. It is errHoldAssigned if the hold was given a copy after
+// the copy was looked up.
+func (r *holdRepo) cancelHold(id string, pickupPeriod time.Duration) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var copyID *string
+
+	if err := tx.QueryRow(`SELECT copy_id FROM hold WHERE id=$1;`, id).Scan(&copyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if copyID != nil {
+		if _, err := tx.Exec(`SELECT id FROM copy WHERE id=$1 FOR UPDATE;`, *copyID); err != nil {
+			return 0, err
+		}
+	}
+
+	var status string
+	var lockedCopyID *string
+
+	if err := tx.QueryRow(`SELECT status, copy_id FROM hold WHERE id=$1 FOR UPDATE;`, id).Scan(&status, &lockedCopyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if copyID == nil && lockedCopyID != nil {
+		return 0, errHoldAssigned
+	}
+
+	if status != models.HoldStatusWaiting && status != models.HoldStatusReady {
+		return 0, errors.New("hold is no longer active")
+	}
+
+	result, err := tx.Exec(`UPDATE hold SET status = $1, updated_at = now() WHERE id = $2`, models.HoldStatusCancelled, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if status == models.HoldStatusReady && copyID != nil {
+		if err := promoteNextHold(tx, *copyID, pickupPeriod); err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+// ExpireHolds closes ready holds whose pickup window has passed and passes their copies on.
+func (r *holdRepo) ExpireHolds(pickupPeriod time.Duration) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// The copies are locked before their holds, in the order CreateLoan and promoteNextHold
+	// lock them. Holds fulfilled or cancelled meanwhile no longer match the update.
+	query := `WITH copies AS (
+		SELECT copy.id FROM copy
+		JOIN hold ON hold.copy_id = copy.id
+		WHERE hold.status = $2 AND hold.pickup_expires_at < now()
+		ORDER BY copy.id
+		FOR UPDATE OF copy SKIP LOCKED
+	)
+	UPDATE hold SET status = $1, updated_at = now()
+	WHERE copy_id IN (SELECT id FROM copies) AND status = $2 AND pickup_expires_at < now()
+	RETURNING copy_id;`
+
+	rows, err := tx.Query(query, models.HoldStatusExpired, models.HoldStatusReady)
+	if err != nil {
+		return 0, err
+	}
+
+	var copyIDs []string
+
+	for rows.Next() {
+		var copyID string
+		if err := rows.Scan(&copyID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		copyIDs = append(copyIDs, copyID)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, copyID := range copyIDs {
+		if err := promoteNextHold(tx, copyID, pickupPeriod); err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(copyIDs)), tx.Commit()
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/saidakhmatov/catalog_of_books/bus"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+// EventChannel is the NOTIFY channel catalog changes are announced on.
+const EventChannel = "catalog_events"
+
+const (
+	listenerMinReconnect = time.Second
+	listenerMaxReconnect = time.Minute
+	// listenerPingInterval is how often an idle listener checks its connection, so a
+	// silently dropped one is noticed and reopened.
+	listenerPingInterval = 90 * time.Second
+)
+
+// Listener publishes the catalog changes made by any API instance, announced with NOTIFY,
+// to the bus of this instance. It reconnects by itself after losing the connection and
+// then publishes a resync notification, as changes made meanwhile were not heard.
+type Listener struct {
+	listener *pq.Listener
+	bus      *bus.Bus
+}
+
+// NewListener connects to the database described by str and listens on EventChannel.
+func NewListener(str string, b *bus.Bus) (*Listener, error) {
+	listener := pq.NewListener(str, listenerMinReconnect, listenerMaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			log.Println("Event listener lost its connection:", err)
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Println("Event listener could not reconnect:", err)
+		case pq.ListenerEventReconnected:
+			log.Println("Event listener reconnected")
+		}
+	})
+
+	if err := listener.Listen(EventChannel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return &Listener{listener: listener, bus: b}, nil
+}
+
+// Run publishes notifications until the listener is closed.
+func (l *Listener) Run() {
+	for {
+		select {
+		case n, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+
+			// pq sends nil after reestablishing a lost connection.
+			if n == nil {
+				l.bus.Publish(models.Notification{Resync: true})
+				continue
+			}
+
+			var notification models.Notification
+			if err := json.Unmarshal([]byte(n.Extra), &notification); err != nil {
+				log.Println("Could not parse event notification:", err)
+				continue
+			}
+
+			l.bus.Publish(notification)
+		case <-time.After(listenerPingInterval):
+			go l.listener.Ping()
+		}
+	}
+}
+
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
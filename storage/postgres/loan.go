@@ -0,0 +1,323 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type loanRepo struct {
+	db  *sqlx.DB
+	ids helper.IDGenerator
+}
+
+// CreateLoan checks a copy out to a member. The copy and member rows are locked for the
+// duration of the transaction so concurrent checkouts can neither lend the same copy twice
+// nor push a member past their borrowing limit. Members owing more than maxBalance are
+// refused; a maxBalance of 0 disables the check.
+func (r *loanRepo) CreateLoan(details models.Loan, maxBalance int64) (string, error) {
+	var resp string
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	var status string
+
+	if err := tx.QueryRow(`SELECT status FROM copy WHERE id=$1 FOR UPDATE;`, details.CopyID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, errors.New("there is no copy with the given id")
+		}
+		return resp, err
+	}
+
+	if status != models.CopyStatusAvailable && status != models.CopyStatusOnHold {
+		return resp, errors.New("copy is not available for loan")
+	}
+
+	if status == models.CopyStatusOnHold {
+		result, err := tx.Exec(
+			`UPDATE hold SET status = $1, updated_at = now() WHERE copy_id = $2 AND member_id = $3 AND status = $4`,
+			models.HoldStatusFulfilled, details.CopyID, details.MemberID, models.HoldStatusReady,
+		)
+		if err != nil {
+			return resp, err
+		}
+
+		fulfilled, err := result.RowsAffected()
+		if err != nil {
+			return resp, err
+		}
+
+		if fulfilled < 1 {
+			return resp, errors.New("copy is held for another member")
+		}
+	}
+
+	var maxLoans int
+
+	if err := tx.QueryRow(`SELECT max_loans FROM member WHERE id=$1 FOR UPDATE;`, details.MemberID).Scan(&maxLoans); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, errors.New("there is no member with the given id")
+		}
+		return resp, err
+	}
+
+	if maxBalance > 0 {
+		balance, err := memberBalance(tx, details.MemberID)
+		if err != nil {
+			return resp, err
+		}
+
+		if balance > maxBalance {
+			return resp, errors.New("member owes more in fines than allowed to borrow")
+		}
+	}
+
+	var activeLoans int
+
+	if err := tx.QueryRow(`SELECT count(1) FROM loan WHERE member_id=$1 AND returned_at IS NULL;`, details.MemberID).Scan(&activeLoans); err != nil {
+		return resp, err
+	}
+
+	if activeLoans >= maxLoans {
+		return resp, errors.New("member has reached the borrowing limit")
+	}
+
+	query := `INSERT INTO loan (
+		id,
+		copy_id,
+		member_id,
+		checked_out_at,
+		due_at,
+		created_at,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7
+	) RETURNING id;`
+
+	row := tx.QueryRow(query,
+		details.ID,
+		details.CopyID,
+		details.MemberID,
+		details.CheckedOutAt,
+		details.DueAt,
+		details.CreatedAt,
+		details.UpdatedAt,
+	)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	if _, err := tx.Exec(`UPDATE copy SET status = $1, updated_at = now() WHERE id = $2`, models.CopyStatusOnLoan, details.CopyID); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+// ReturnLoan closes an open loan, charges the overdue fine computed for it and hands its
+// copy to the next hold in the queue, or puts it back on the shelf when nobody is waiting.
+func (r *loanRepo) ReturnLoan(id string, returnedAt time.Time, fine int64, holdPickupPeriod time.Duration) (models.Loan, error) {
+	var resp models.Loan
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	var previouslyReturnedAt *time.Time
+
+	if err := tx.QueryRow(`SELECT returned_at FROM loan WHERE id=$1 FOR UPDATE;`, id).Scan(&previouslyReturnedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, errors.New("there is no loan with the given id")
+		}
+		return resp, err
+	}
+
+	if previouslyReturnedAt != nil {
+		return resp, errors.New("loan has already been returned")
+	}
+
+	query := `UPDATE loan SET returned_at = $2, updated_at = now() WHERE id = $1
+	RETURNING
+		id,
+		copy_id,
+		(SELECT book_id FROM copy WHERE copy.id = loan.copy_id),
+		member_id,
+		checked_out_at,
+		due_at,
+		returned_at,
+		created_at,
+		updated_at;`
+
+	row := tx.QueryRow(query, id, returnedAt)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.CopyID,
+		&resp.BookID,
+		&resp.MemberID,
+		&resp.CheckedOutAt,
+		&resp.DueAt,
+		&resp.ReturnedAt,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	if fine > 0 {
+		if _, err := tx.Exec(
+			`INSERT INTO fine (id, member_id, loan_id, kind, amount, note, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			r.ids.NewID(), resp.MemberID, resp.ID, models.FineKindCharge, fine, "overdue return", returnedAt,
+		); err != nil {
+			return resp, err
+		}
+	}
+
+	var copyStatus string
+
+	if err := tx.QueryRow(`SELECT status FROM copy WHERE id=$1 FOR UPDATE;`, resp.CopyID).Scan(&copyStatus); err != nil {
+		return resp, err
+	}
+
+	if copyStatus == models.CopyStatusOnLoan {
+		if err := promoteNextHold(tx, resp.CopyID, holdPickupPeriod); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, tx.Commit()
+}
+
+func (r *loanRepo) GetLoan(id string) (models.Loan, error) {
+	var resp models.Loan
+
+	query := `
+				SELECT
+					loan.id,
+					loan.copy_id,
+					copy.book_id,
+					loan.member_id,
+					loan.checked_out_at,
+					loan.due_at,
+					loan.returned_at,
+					loan.created_at,
+					loan.updated_at
+				FROM
+					loan
+				JOIN copy ON copy.id = loan.copy_id
+				WHERE loan.id=$1;
+			`
+
+	row := r.db.QueryRow(query, id)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.CopyID,
+		&resp.BookID,
+		&resp.MemberID,
+		&resp.CheckedOutAt,
+		&resp.DueAt,
+		&resp.ReturnedAt,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *loanRepo) GetAllLoans(queryParam models.LoanQueryParamModel) ([]models.Loan, error) {
+	var resp []models.Loan = []models.Loan{}
+
+	params := make(map[string]interface{})
+
+	query := `SELECT
+		loan.id,
+		loan.copy_id,
+		copy.book_id,
+		loan.member_id,
+		loan.checked_out_at,
+		loan.due_at,
+		loan.returned_at,
+		loan.created_at,
+		loan.updated_at
+	FROM
+		loan
+	JOIN copy ON copy.id = loan.copy_id`
+	filter := " WHERE 1=1"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.MemberID) > 0 {
+		params["member_id"] = queryParam.MemberID
+		filter += " AND loan.member_id = :member_id"
+	}
+
+	if queryParam.ActiveOnly {
+		filter += " AND loan.returned_at IS NULL"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	q := query + filter + " ORDER BY loan.checked_out_at DESC" + offset + limit
+	rows, err := r.db.NamedQuery(q, params)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var loan models.Loan
+		err = rows.Scan(
+			&loan.ID,
+			&loan.CopyID,
+			&loan.BookID,
+			&loan.MemberID,
+			&loan.CheckedOutAt,
+			&loan.DueAt,
+			&loan.ReturnedAt,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, loan)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type memberRepo struct {
+	db *sqlx.DB
+}
+
+func (r *memberRepo) CreateMember(entity models.Member) (string, error) {
+	var resp string
+
+	query := `INSERT INTO member (
+		id,
+		firstname,
+		lastname,
+		email,
+		phone,
+		max_loans,
+		created_at,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7,
+		$8
+	) RETURNING id;`
+
+	row := r.db.QueryRow(query,
+		entity.ID,
+		entity.Firstname,
+		entity.Lastname,
+		entity.Email,
+		entity.Phone,
+		entity.MaxLoans,
+		entity.CreatedAt,
+		entity.UpdatedAt,
+	)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (r *memberRepo) GetMember(id string) (models.Member, error) {
+	var resp models.Member
+
+	query := `
+				SELECT
+					id,
+					firstname,
+					lastname,
+					email,
+					phone,
+					max_loans,
+					created_at,
+					updated_at
+				FROM
+					member
+				WHERE id=$1;
+			`
+
+	row := r.db.QueryRow(query, id)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.Firstname,
+		&resp.Lastname,
+		&resp.Email,
+		&resp.Phone,
+		&resp.MaxLoans,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *memberRepo) GetAllMembers(queryParam models.ApplicationQueryParamModel) ([]models.Member, error) {
+	var resp []models.Member = []models.Member{}
+
+	params := make(map[string]interface{})
+
+	query := `SELECT
+		id,
+		firstname,
+		lastname,
+		email,
+		phone,
+		max_loans,
+		created_at,
+		updated_at
+	FROM
+		member`
+	filter := " WHERE 1=1"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.Search) > 0 {
+		params["search"] = queryParam.Search
+		filter += " AND (firstname ILIKE '%' || :search || '%' OR lastname ILIKE '%' || :search || '%' OR email ILIKE '%' || :search || '%')"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	q := query + filter + " ORDER BY lastname, firstname" + offset + limit
+	rows, err := r.db.NamedQuery(q, params)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member models.Member
+		err = rows.Scan(
+			&member.ID,
+			&member.Firstname,
+			&member.Lastname,
+			&member.Email,
+			&member.Phone,
+			&member.MaxLoans,
+			&member.CreatedAt,
+			&member.UpdatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, member)
+	}
+
+	return resp, nil
+}
+
+func (r *memberRepo) UpdateMember(entity models.UpdateMember, id string) (int64, error) {
+	params := make(map[string]interface{})
+
+	params["id"] = id
+
+	query := `UPDATE member SET `
+
+	if len(entity.Firstname) > 0 {
+		params["firstname"] = entity.Firstname
+		query += `firstname = :firstname,`
+	}
+
+	if len(entity.Lastname) > 0 {
+		params["lastname"] = entity.Lastname
+		query += `lastname = :lastname,`
+	}
+
+	if len(entity.Email) > 0 {
+		params["email"] = entity.Email
+		query += `email = :email,`
+	}
+
+	if len(entity.Phone) > 0 {
+		params["phone"] = entity.Phone
+		query += `phone = :phone,`
+	}
+
+	if entity.MaxLoans > 0 {
+		params["max_loans"] = entity.MaxLoans
+		query += `max_loans = :max_loans,`
+	}
+
+	query += `updated_at = now() WHERE id =:id`
+
+	result, err := r.db.NamedExec(query, params)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, err
+}
+
+func (r *memberRepo) DeleteMember(id string) (int64, error) {
+	query := `DELETE FROM member WHERE id = $1`
+
+	result, err := r.db.Exec(query, id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, err
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"log"
 
+	"github.com/saidakhmatov/catalog_of_books/helper"
 	"github.com/saidakhmatov/catalog_of_books/storage"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -13,9 +14,20 @@ type postgres struct {
 	authorRepo       *authorRepo
 	bookCategoryRepo *bookCategoryRepo
 	bookRepo         *bookRepo
+	seriesRepo       *seriesRepo
+	copyRepo         *copyRepo
+	memberRepo       *memberRepo
+	loanRepo         *loanRepo
+	holdRepo         *holdRepo
+	fineRepo         *fineRepo
+	reviewRepo       *reviewRepo
+	coverRepo        *coverRepo
+	auditRepo        *auditRepo
+	webhookRepo      *webhookRepo
+	eventRepo        *eventRepo
 }
 
-func NewPostgres(str string) storage.StorageI {
+func NewPostgres(str string, ids helper.IDGenerator) storage.StorageI {
 	
 	db, err := sqlx.Connect("postgres", str)
 	
@@ -34,6 +46,17 @@ func NewPostgres(str string) storage.StorageI {
 		authorRepo:       &authorRepo{db},
 		bookRepo:         &bookRepo{db},
 		bookCategoryRepo: &bookCategoryRepo{db},
+		seriesRepo:       &seriesRepo{db},
+		copyRepo:         &copyRepo{db},
+		memberRepo:       &memberRepo{db},
+		loanRepo:         &loanRepo{db, ids},
+		holdRepo:         &holdRepo{db},
+		fineRepo:         &fineRepo{db},
+		reviewRepo:       &reviewRepo{db},
+		coverRepo:        &coverRepo{db},
+		auditRepo:        &auditRepo{db},
+		webhookRepo:      &webhookRepo{db, ids},
+		eventRepo:        &eventRepo{db},
 	}
 }
 
@@ -52,3 +75,47 @@ func (pg *postgres) BookCategoryRepo() storage.BookCategoryI {
 func (pg *postgres) BookRepo() storage.BookI {
 	return pg.bookRepo
 }
+
+func (pg *postgres) SeriesRepo() storage.SeriesI {
+	return pg.seriesRepo
+}
+
+func (pg *postgres) CopyRepo() storage.CopyI {
+	return pg.copyRepo
+}
+
+func (pg *postgres) MemberRepo() storage.MemberI {
+	return pg.memberRepo
+}
+
+func (pg *postgres) LoanRepo() storage.LoanI {
+	return pg.loanRepo
+}
+
+func (pg *postgres) HoldRepo() storage.HoldI {
+	return pg.holdRepo
+}
+
+func (pg *postgres) FineRepo() storage.FineI {
+	return pg.fineRepo
+}
+
+func (pg *postgres) ReviewRepo() storage.ReviewI {
+	return pg.reviewRepo
+}
+
+func (pg *postgres) CoverRepo() storage.CoverI {
+	return pg.coverRepo
+}
+
+func (pg *postgres) AuditRepo() storage.AuditI {
+	return pg.auditRepo
+}
+
+func (pg *postgres) WebhookRepo() storage.WebhookI {
+	return pg.webhookRepo
+}
+
+func (pg *postgres) EventRepo() storage.EventI {
+	return pg.eventRepo
+}
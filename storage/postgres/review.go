@@ -0,0 +1,310 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type reviewRepo struct {
+	db *sqlx.DB
+}
+
+// lockBookRatings locks the book row so that concurrent review changes recompute its
+// rating aggregates one after another.
+func lockBookRatings(tx *sqlx.Tx, bookID string) error {
+	var id string
+
+	if err := tx.QueryRow(`SELECT id FROM book WHERE id=$1 FOR UPDATE;`, bookID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("there is no book with the given id")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// refreshBookRatings recomputes the denormalized average_rating and rating_count of a book
+// and records the change of the book as made by actor, so that it reaches the change
+// feeds like any other change of the book.
+func refreshBookRatings(tx *sqlx.Tx, bookID, actor string) error {
+	before, err := lockedRow(tx, "book", bookID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE book SET
+		average_rating = COALESCE((SELECT round(avg(rating), 2) FROM review WHERE book_id = $1), 0),
+		rating_count = (SELECT count(1) FROM review WHERE book_id = $1)
+	WHERE id = $1`
+
+	if _, err := tx.Exec(query, bookID); err != nil {
+		return err
+	}
+
+	after, err := lockedRow(tx, "book", bookID)
+	if err != nil {
+		return err
+	}
+
+	return recordChange(tx, "book", bookID, actor, models.AuditUpdate, before, after)
+}
+
+func (r *reviewRepo) CreateReview(details models.Review, actor string) (string, error) {
+	var resp string
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBookRatings(tx, details.BookID); err != nil {
+		return resp, err
+	}
+
+	var countReviewer int
+
+	if err := tx.QueryRow(`SELECT count(1) FROM member WHERE id=$1;`, details.ReviewerID).Scan(&countReviewer); err != nil {
+		return resp, err
+	}
+
+	if countReviewer < 1 {
+		return resp, errors.New("there is no member with the given id")
+	}
+
+	var countExisting int
+
+	if err := tx.QueryRow(`SELECT count(1) FROM review WHERE book_id=$1 AND reviewer_id=$2;`, details.BookID, details.ReviewerID).Scan(&countExisting); err != nil {
+		return resp, err
+	}
+
+	if countExisting > 0 {
+		return resp, errors.New("reviewer has already reviewed this book")
+	}
+
+	query := `INSERT INTO review (
+		id,
+		book_id,
+		reviewer_id,
+		rating,
+		text,
+		created_at,
+		updated_at
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7
+	) RETURNING id;`
+
+	row := tx.QueryRow(query,
+		details.ID,
+		details.BookID,
+		details.ReviewerID,
+		details.Rating,
+		details.Text,
+		details.CreatedAt,
+		details.UpdatedAt,
+	)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	if err := refreshBookRatings(tx, details.BookID, actor); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepo) GetReview(bookID, id string) (models.Review, error) {
+	var resp models.Review
+
+	query := `
+				SELECT
+					id,
+					book_id,
+					reviewer_id,
+					rating,
+					text,
+					created_at,
+					updated_at
+				FROM
+					review
+				WHERE id=$1 AND book_id=$2;
+			`
+
+	row := r.db.QueryRow(query, id, bookID)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.BookID,
+		&resp.ReviewerID,
+		&resp.Rating,
+		&resp.Text,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepo) GetAllReviews(bookID string, queryParam models.ApplicationQueryParamModel) ([]models.Review, error) {
+	var resp []models.Review = []models.Review{}
+
+	params := make(map[string]interface{})
+
+	params["book_id"] = bookID
+
+	query := `SELECT
+		id,
+		book_id,
+		reviewer_id,
+		rating,
+		text,
+		created_at,
+		updated_at
+	FROM
+		review`
+	filter := " WHERE book_id = :book_id"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.Search) > 0 {
+		params["search"] = queryParam.Search
+		filter += " AND (text ILIKE '%' || :search || '%')"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	q := query + filter + " ORDER BY created_at DESC, id" + offset + limit
+	rows, err := r.db.NamedQuery(q, params)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review models.Review
+		err = rows.Scan(
+			&review.ID,
+			&review.BookID,
+			&review.ReviewerID,
+			&review.Rating,
+			&review.Text,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, review)
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepo) UpdateReview(entity models.UpdateReview, bookID, id, actor string) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBookRatings(tx, bookID); err != nil {
+		return 0, err
+	}
+
+	params := make(map[string]interface{})
+
+	params["id"] = id
+	params["book_id"] = bookID
+
+	query := `UPDATE review SET `
+
+	if entity.Rating > 0 {
+		params["rating"] = entity.Rating
+		query += `rating = :rating,`
+	}
+
+	if len(entity.Text) > 0 {
+		params["text"] = entity.Text
+		query += `text = :text,`
+	}
+
+	query += `updated_at = now() WHERE id =:id AND book_id =:book_id`
+
+	result, err := tx.NamedExec(query, params)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := refreshBookRatings(tx, bookID, actor); err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+func (r *reviewRepo) DeleteReview(bookID, id, actor string) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBookRatings(tx, bookID); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM review WHERE id = $1 AND book_id = $2`, id, bookID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := refreshBookRatings(tx, bookID, actor); err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+type seriesRepo struct {
+	db *sqlx.DB
+}
+
+func (r *seriesRepo) CreateSeries(entity models.Series) (string, error) {
+	var resp string
+
+	query := `INSERT INTO series (id, series_name, description, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+
+	row := r.db.QueryRow(query, entity.ID, entity.SeriesName, entity.Description, entity.CreatedAt, entity.UpdatedAt)
+
+	if err := row.Scan(&resp); err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (r *seriesRepo) GetSeries(id string) (models.Series, error) {
+	var resp models.Series
+
+	query := `
+				SELECT
+					id,
+					series_name,
+					description,
+					created_at,
+					updated_at
+				FROM
+					series
+				WHERE id=$1;
+			`
+
+	row := r.db.QueryRow(query, id)
+
+	if err := row.Scan(
+		&resp.ID,
+		&resp.SeriesName,
+		&resp.Description,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (r *seriesRepo) GetAllSeries(queryParam models.ApplicationQueryParamModel) ([]models.Series, error) {
+	var resp []models.Series = []models.Series{}
+
+	params := make(map[string]interface{})
+
+	query := `SELECT
+		id,
+		series_name,
+		description,
+		created_at,
+		updated_at
+	FROM
+		series`
+	filter := " WHERE 1=1"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.Search) > 0 {
+		params["search"] = queryParam.Search
+		filter += " AND (series_name ILIKE '%' || :search || '%')"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	q := query + filter + " ORDER BY series_name" + offset + limit
+	rows, err := r.db.NamedQuery(q, params)
+
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var series models.Series
+		err = rows.Scan(
+			&series.ID,
+			&series.SeriesName,
+			&series.Description,
+			&series.CreatedAt,
+			&series.UpdatedAt,
+		)
+
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, series)
+	}
+
+	return resp, nil
+}
+
+func (r *seriesRepo) UpdateSeries(entity models.UpdateSeries, id string) (int64, error) {
+	params := make(map[string]interface{})
+
+	params["id"] = id
+
+	query := `UPDATE series SET `
+
+	if len(entity.SeriesName) > 0 {
+		params["series_name"] = entity.SeriesName
+		query += `series_name = :series_name,`
+	}
+
+	if len(entity.Description) > 0 {
+		params["description"] = entity.Description
+		query += `description = :description,`
+	}
+
+	query += `updated_at = now() WHERE id =:id`
+
+	result, err := r.db.NamedExec(query, params)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, err
+}
+
+// DeleteSeries detaches the member books before removing the series itself. Each
+// detached book is recorded as changed by actor.
+func (r *seriesRepo) DeleteSeries(id, actor string) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var bookIDs []string
+	if err := tx.Select(&bookIDs, `SELECT id FROM book WHERE series_id = $1 ORDER BY id FOR UPDATE`, id); err != nil {
+		return 0, err
+	}
+
+	for _, bookID := range bookIDs {
+		query := `UPDATE book SET series_id = NULL, series_position = NULL, updated_at = now() WHERE id = $1`
+		if _, err := updateBookSeries(tx, bookID, actor, query, bookID); err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM series WHERE id = $1`, id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+func (r *seriesRepo) GetSeriesBooks(id string) ([]models.Book, error) {
+	var resp []models.Book = []models.Book{}
+
+	query := `SELECT
+		id,
+		slug,
+		category_id,
+		author_id,
+		book_name,
+		publication_year,
+		language,
+		page_count,
+		description,
+		edition,
+		format,
+		series_id,
+		series_position,
+		average_rating,
+		rating_count,
+		created_at,
+		updated_at
+	FROM
+		book
+	WHERE series_id = $1
+	ORDER BY series_position, book_name`
+
+	rows, err := r.db.Query(query, id)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.Book
+		err = rows.Scan(
+			&book.ID,
+			&book.Slug,
+			&book.CategoryID,
+			&book.AuthorID,
+			&book.BookName,
+			&book.PublicationYear,
+			&book.Language,
+			&book.PageCount,
+			&book.Description,
+			&book.Edition,
+			&book.Format,
+			&book.SeriesID,
+			&book.SeriesPosition,
+			&book.AverageRating,
+			&book.RatingCount,
+			&book.CreatedAt,
+			&book.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, book)
+	}
+
+	return resp, rows.Err()
+}
+
+func (r *seriesRepo) AddBookToSeries(seriesID, bookID string, position float64, actor string) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var countSeries int
+
+	row := tx.QueryRow(`SELECT count(1) FROM series WHERE id=$1;`, seriesID)
+
+	if err := row.Scan(&countSeries); err != nil {
+		return 0, err
+	}
+
+	if countSeries < 1 {
+		return 0, errors.New("there is no series with the given id")
+	}
+
+	query := `UPDATE book SET series_id = $1, series_position = $2, updated_at = now() WHERE id = $3`
+
+	rowsAffected, err := updateBookSeries(tx, bookID, actor, query, seriesID, position, bookID)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+func (r *seriesRepo) RemoveBookFromSeries(seriesID, bookID, actor string) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE book SET series_id = NULL, series_position = NULL, updated_at = now() WHERE id = $1 AND series_id = $2`
+
+	rowsAffected, err := updateBookSeries(tx, bookID, actor, query, bookID, seriesID)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+// updateBookSeries runs query, which changes the series of the book bookID, and records
+// the change of the book as made by actor. A book that does not exist affects nothing.
+func updateBookSeries(tx *sqlx.Tx, bookID, actor, query string, args ...interface{}) (int64, error) {
+	before, err := lockedRow(tx, "book", bookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := lockedRow(tx, "book", bookID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := recordChange(tx, "book", bookID, actor, models.AuditUpdate, before, after); err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+)
+
+// lookupColumn picks the column a path key is compared with: a well-formed id is looked
+// up by id and anything else by slug, which keeps the id column's uuid type out of slug
+// comparisons.
+func lookupColumn(key string) string {
+	if helper.IsID(key) {
+		return "id"
+	}
+	return "slug"
+}
+
+// uniqueSlug slugifies name and, if another row of table already uses the slug or used it
+// before a rename, appends the smallest free "-N" suffix. Rows without any transliterable
+// characters fall back to their id. table is always one of the constant table names.
+func uniqueSlug(q sqlx.Queryer, table, name, id string) (string, error) {
+	base := helper.Slugify(name)
+	if len(base) == 0 {
+		return id, nil
+	}
+
+	query := fmt.Sprintf(`SELECT slug FROM %[1]s WHERE id <> $2 AND (slug = $1 OR slug LIKE $1 || '-%%')
+		UNION
+		SELECT h.slug FROM slug_history h JOIN %[1]s t ON t.id = h.entity_id
+		WHERE h.entity = $3 AND h.entity_id <> $2 AND (h.slug = $1 OR h.slug LIKE $1 || '-%%')`, table)
+
+	rows, err := q.Query(query, base, id, table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", err
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+
+	return slug, nil
+}
+
+// renameSlug gives the row id of table a slug derived from its new name and keeps the old
+// slug in the history so links to it can still be redirected. It must run in the
+// transaction that renames the row.
+func renameSlug(tx *sqlx.Tx, table, name, id string) error {
+	var current string
+	err := tx.QueryRow(fmt.Sprintf(`SELECT slug FROM %s WHERE id = $1 FOR UPDATE`, table), id).Scan(&current)
+	if err != nil {
+		return err
+	}
+
+	slug, err := uniqueSlug(tx, table, name, id)
+	if err != nil {
+		return err
+	}
+
+	if slug == current {
+		return nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO slug_history (entity, slug, entity_id) VALUES ($1, $2, $3)
+		ON CONFLICT (entity, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = now()`, table, current, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM slug_history WHERE entity = $1 AND slug = $2`, table, slug); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`UPDATE %s SET slug = $1 WHERE id = $2`, table), slug, id)
+
+	return err
+}
+
+// slugRedirect returns the current slug of the row of table that used slug before a rename.
+func slugRedirect(q sqlx.Queryer, table, slug string) (string, error) {
+	var resp string
+
+	query := fmt.Sprintf(`SELECT t.slug FROM slug_history h JOIN %s t ON t.id = h.entity_id
+		WHERE h.entity = $1 AND h.slug = $2`, table)
+
+	err := q.QueryRowx(query, table, slug).Scan(&resp)
+
+	return resp, err
+}
@@ -0,0 +1,15 @@
+package postgres
+
+import "fmt"
+
+// tableAsOf returns a FROM item, aliased as table, holding the rows of table as they were
+// at the time bound to the placeholder at, e.g. "$2" or ":as_of". The rows are rebuilt from
+// row_version, which triggers keep for book, author and book_category. table is always
+// one of those constant table names.
+func tableAsOf(table, at string) string {
+	return fmt.Sprintf(`(
+		SELECT (jsonb_populate_record(CAST(NULL AS %[1]s), data)).*
+		FROM row_version
+		WHERE entity = '%[1]s' AND valid_from <= %[2]s AND (valid_to IS NULL OR valid_to > %[2]s)
+	) AS %[1]s`, table, at)
+}
@@ -0,0 +1,483 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/saidakhmatov/catalog_of_books/helper"
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
+
+type webhookRepo struct {
+	db  *sqlx.DB
+	ids helper.IDGenerator
+}
+
+const webhookColumns = `
+		id,
+		url,
+		events,
+		active,
+		created_at,
+		updated_at`
+
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var resp models.Webhook
+
+	err := row.Scan(
+		&resp.ID,
+		&resp.URL,
+		pq.Array(&resp.Events),
+		&resp.Active,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	)
+
+	return resp, err
+}
+
+func (r *webhookRepo) CreateWebhook(entity models.Webhook) (models.Webhook, error) {
+	query := `INSERT INTO webhook (id, url, secret, events, active, created_at, updated_at)
+		VALUES ($1, $2, $3, COALESCE($4, '{}'::text[]), $5, $6, $7)
+		RETURNING` + webhookColumns
+
+	resp, err := scanWebhook(r.db.QueryRow(query,
+		entity.ID,
+		entity.URL,
+		entity.Secret,
+		pq.Array(entity.Events),
+		entity.Active,
+		entity.CreatedAt,
+		entity.UpdatedAt,
+	))
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Secret = entity.Secret
+
+	return resp, nil
+}
+
+func (r *webhookRepo) GetWebhook(id string) (models.Webhook, error) {
+	query := `SELECT` + webhookColumns + `
+		FROM webhook
+		WHERE id = $1`
+
+	return scanWebhook(r.db.QueryRow(query, id))
+}
+
+func (r *webhookRepo) GetAllWebhooks(queryParam models.ApplicationQueryParamModel) ([]models.Webhook, error) {
+	var resp []models.Webhook = []models.Webhook{}
+
+	params := make(map[string]interface{})
+
+	query := `SELECT` + webhookColumns + `
+	FROM
+		webhook`
+	filter := " WHERE 1=1"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.Search) > 0 {
+		params["search"] = queryParam.Search
+		filter += " AND (url ILIKE '%' || :search || '%')"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	rows, err := r.db.NamedQuery(query+filter+" ORDER BY created_at, id"+offset+limit, params)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, webhook)
+	}
+
+	return resp, rows.Err()
+}
+
+func (r *webhookRepo) UpdateWebhook(entity models.UpdateWebhook, id string) (models.Webhook, error) {
+	params := make(map[string]interface{})
+
+	params["id"] = id
+
+	query := `UPDATE webhook SET `
+
+	if len(entity.URL) > 0 {
+		params["url"] = entity.URL
+		query += `url = :url,`
+	}
+
+	if len(entity.Secret) > 0 {
+		params["secret"] = entity.Secret
+		query += `secret = :secret,`
+	}
+
+	if entity.Events != nil {
+		params["events"] = pq.Array(entity.Events)
+		query += `events = :events,`
+	}
+
+	if entity.Active != nil {
+		params["active"] = *entity.Active
+		query += `active = :active,`
+	}
+
+	query += `updated_at = now() WHERE id = :id RETURNING` + webhookColumns
+
+	q, args, err := sqlx.Named(query, params)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return scanWebhook(r.db.QueryRowx(r.db.Rebind(q), args...))
+}
+
+func (r *webhookRepo) DeleteWebhook(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM webhook WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+const deliveryColumns = `
+		d.id,
+		d.webhook_id,
+		d.event_id,
+		o.event_type,
+		d.status,
+		d.attempts,
+		d.next_attempt_at,
+		d.last_status_code,
+		d.last_error,
+		d.delivered_at,
+		d.created_at,
+		d.updated_at`
+
+func scanDelivery(row rowScanner) (models.WebhookDelivery, error) {
+	var resp models.WebhookDelivery
+	var nextAttemptAt time.Time
+	var lastStatusCode sql.NullInt64
+
+	err := row.Scan(
+		&resp.ID,
+		&resp.WebhookID,
+		&resp.EventID,
+		&resp.EventType,
+		&resp.Status,
+		&resp.Attempts,
+		&nextAttemptAt,
+		&lastStatusCode,
+		&resp.LastError,
+		&resp.DeliveredAt,
+		&resp.CreatedAt,
+		&resp.UpdatedAt,
+	)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.Status == models.DeliveryPending {
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+	if lastStatusCode.Valid {
+		code := int(lastStatusCode.Int64)
+		resp.LastStatusCode = &code
+	}
+
+	return resp, nil
+}
+
+// GetDeliveries returns the deliveries of a webhook, newest first, optionally only those
+// with the given status.
+func (r *webhookRepo) GetDeliveries(webhookID string, queryParam models.WebhookDeliveryQueryParamModel) ([]models.WebhookDelivery, error) {
+	var resp []models.WebhookDelivery = []models.WebhookDelivery{}
+
+	params := make(map[string]interface{})
+
+	params["webhook_id"] = webhookID
+
+	query := `SELECT` + deliveryColumns + `
+	FROM
+		webhook_delivery d
+		JOIN outbox o ON o.id = d.event_id`
+	filter := " WHERE d.webhook_id = :webhook_id"
+	offset := " OFFSET 0"
+	limit := " LIMIT 10"
+
+	if len(queryParam.Status) > 0 {
+		params["status"] = queryParam.Status
+		filter += " AND d.status = :status"
+	}
+
+	if queryParam.Offset > 0 {
+		params["offset"] = queryParam.Offset
+		offset = " OFFSET :offset"
+	}
+
+	if queryParam.Limit > 0 {
+		params["limit"] = queryParam.Limit
+		limit = " LIMIT :limit"
+	}
+
+	rows, err := r.db.NamedQuery(query+filter+" ORDER BY d.created_at DESC, d.id"+offset+limit, params)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, delivery)
+	}
+
+	return resp, rows.Err()
+}
+
+// GetDelivery returns a delivery of a webhook with the log of its attempts.
+func (r *webhookRepo) GetDelivery(webhookID, id string) (models.WebhookDelivery, error) {
+	query := `SELECT` + deliveryColumns + `
+		FROM webhook_delivery d
+		JOIN outbox o ON o.id = d.event_id
+		WHERE d.webhook_id = $1 AND d.id = $2`
+
+	resp, err := scanDelivery(r.db.QueryRow(query, webhookID, id))
+	if err != nil {
+		return resp, err
+	}
+
+	rows, err := r.db.Query(`SELECT attempt, status_code, error, duration_ms, created_at
+		FROM webhook_attempt
+		WHERE delivery_id = $1
+		ORDER BY attempt`, id)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	resp.AttemptLog = []models.WebhookAttempt{}
+	for rows.Next() {
+		var attempt models.WebhookAttempt
+		var statusCode sql.NullInt64
+
+		err := rows.Scan(&attempt.Attempt, &statusCode, &attempt.Error, &attempt.DurationMS, &attempt.CreatedAt)
+		if err != nil {
+			return resp, err
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			attempt.StatusCode = &code
+		}
+		resp.AttemptLog = append(resp.AttemptLog, attempt)
+	}
+
+	return resp, rows.Err()
+}
+
+// RetryDelivery puts a dead-lettered delivery back in the queue with a fresh set of attempts.
+func (r *webhookRepo) RetryDelivery(webhookID, id string) (int64, error) {
+	result, err := r.db.Exec(`UPDATE webhook_delivery
+		SET status = $3, attempts = 0, next_attempt_at = now(), updated_at = now()
+		WHERE webhook_id = $1 AND id = $2 AND status = $4`, webhookID, id, models.DeliveryPending, models.DeliveryDead)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// FanOutEvents creates a delivery to every active subscribed webhook for up to limit
+// events of the outbox that have not been dispatched yet, and marks them dispatched.
+// Concurrent dispatchers skip the events another one is fanning out.
+func (r *webhookRepo) FanOutEvents(limit int) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	type event struct {
+		id        int64
+		eventType string
+	}
+
+	rows, err := tx.Query(`SELECT id, event_type FROM outbox
+		WHERE dispatched_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []event
+	var eventIDs []int64
+	for rows.Next() {
+		var e event
+		if err := rows.Scan(&e.id, &e.eventType); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, e)
+		eventIDs = append(eventIDs, e.id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	rows, err = tx.Query(`SELECT id, events FROM webhook WHERE active`)
+	if err != nil {
+		return 0, err
+	}
+
+	type subscription struct {
+		id     string
+		events []string
+	}
+
+	var subscriptions []subscription
+	for rows.Next() {
+		var s subscription
+		if err := rows.Scan(&s.id, pq.Array(&s.events)); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for _, e := range events {
+		for _, s := range subscriptions {
+			if len(s.events) > 0 && !helper.Contains(s.events, e.eventType) {
+				continue
+			}
+
+			_, err := tx.Exec(`INSERT INTO webhook_delivery (id, webhook_id, event_id) VALUES ($1, $2, $3)`, r.ids.NewID(), s.id, e.id)
+			if err != nil {
+				return 0, err
+			}
+			created++
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE outbox SET dispatched_at = now() WHERE id = ANY($1)`, pq.Array(eventIDs)); err != nil {
+		return 0, err
+	}
+
+	return created, tx.Commit()
+}
+
+// ClaimDeliveries returns up to limit pending deliveries that are due and holds them back
+// from other dispatchers for lease, the time allowed to send them.
+func (r *webhookRepo) ClaimDeliveries(limit int, lease time.Duration) ([]models.DueDelivery, error) {
+	var resp []models.DueDelivery
+
+	rows, err := r.db.Query(`UPDATE webhook_delivery d
+		SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		FROM (
+			SELECT id FROM webhook_delivery
+			WHERE status = $3 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due, webhook w, outbox o
+		WHERE d.id = due.id AND w.id = d.webhook_id AND o.id = d.event_id
+		RETURNING d.id, d.webhook_id, d.event_id, o.event_type, w.url, w.secret, d.attempts, o.payload`,
+		limit, lease.Milliseconds(), models.DeliveryPending)
+	if err != nil {
+		return resp, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery models.DueDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.URL,
+			&delivery.Secret,
+			&delivery.Attempts,
+			&delivery.Payload,
+		)
+		if err != nil {
+			return resp, err
+		}
+		resp = append(resp, delivery)
+	}
+
+	return resp, rows.Err()
+}
+
+// RecordAttempt logs an attempt at a delivery and moves it on: delivered, due again at
+// retryAt, or dead-lettered when retryAt is nil.
+func (r *webhookRepo) RecordAttempt(deliveryID string, attempt models.WebhookAttempt, delivered bool, retryAt *time.Time) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO webhook_attempt (delivery_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5)`, deliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.DurationMS)
+	if err != nil {
+		return err
+	}
+
+	status := models.DeliveryDead
+	nextAttemptAt := time.Now()
+	switch {
+	case delivered:
+		status = models.DeliveryDelivered
+	case retryAt != nil:
+		status = models.DeliveryPending
+		nextAttemptAt = *retryAt
+	}
+
+	_, err = tx.Exec(`UPDATE webhook_delivery SET
+			status = $2,
+			attempts = $3,
+			next_attempt_at = $4,
+			last_status_code = $5,
+			last_error = $6,
+			delivered_at = CASE WHEN $7 THEN now() END,
+			updated_at = now()
+		WHERE id = $1`, deliveryID, status, attempt.Attempt, nextAttemptAt, attempt.StatusCode, attempt.Error, delivered)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
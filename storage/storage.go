@@ -1,34 +1,167 @@
 package storage
 
-import "github.com/saidakhmatov/catalog_of_books/models"
+import (
+	"errors"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/models"
+)
 
 type StorageI interface {
 	CloseDB() error
 	BookCategoryRepo() BookCategoryI
 	BookRepo() BookI
 	AuthorRepo() AuthorI
+	SeriesRepo() SeriesI
+	CopyRepo() CopyI
+	MemberRepo() MemberI
+	LoanRepo() LoanI
+	HoldRepo() HoldI
+	FineRepo() FineI
+	ReviewRepo() ReviewI
+	CoverRepo() CoverI
+	AuditRepo() AuditI
+	WebhookRepo() WebhookI
+	EventRepo() EventI
+}
+
+// ErrBlobNotFound is returned by BlobStorageI implementations for unknown keys.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStorageI stores binary objects such as cover images under slash separated keys.
+type BlobStorageI interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
 }
 
 type BookCategoryI interface {
-	GetBookCategory(id string) (models.BookCategory, error)
+	GetBookCategory(id string, fields ...string) (models.BookCategory, error)
+	GetBookCategoryAsOf(id string, asOf time.Time, fields ...string) (models.BookCategory, error)
 	GetAllBookCategories(queryParam models.ApplicationQueryParamModel) ([]models.BookCategory, error)
-	CreateBookCategory(details models.BookCategory) (string, error)
-	UpdateBookCategory(details *models.UpdateBookCategory, id string) (int64, error)
-	DeleteBookCategory(id string) (int64, error)
+	GetBookCategoriesByIDs(ids []string, fields ...string) ([]models.BookCategory, error)
+	CreateBookCategory(details models.BookCategory, actor string) (models.BookCategory, error)
+	UpdateBookCategory(details *models.UpdateBookCategory, id, actor string) (models.BookCategory, error)
+	DeleteBookCategory(id, actor string) (int64, error)
+	GetSlugRedirect(slug string) (string, error)
 }
 
 type BookI interface {
-	GetBook(id string) (models.Book, error)
-	GetAllBooks(queryParam models.ApplicationQueryParamModel) ([]models.Book, error)
-	CreateBook(details models.Book) (string, error)
-	UpdateBook(details models.UpdateBook, id string) (int64, error)
-	DeleteBook(id string) (int64, error)
+	GetBook(id string, fields ...string) (models.Book, error)
+	GetBookAsOf(id string, asOf time.Time, fields ...string) (models.Book, error)
+	GetAllBooks(queryParam models.BookQueryParamModel) ([]models.Book, error)
+	GetBooksByIDs(ids []string, fields ...string) ([]models.Book, error)
+	GetBooksByAuthorIDs(authorIDs []string, limit int, fields ...string) ([]models.Book, error)
+	GetBooksByCategoryIDs(categoryIDs []string, limit int, fields ...string) ([]models.Book, error)
+	GetBooksBySeriesIDs(seriesIDs []string, limit int, fields ...string) ([]models.Book, error)
+	CreateBook(details models.Book, actor string) (models.Book, error)
+	UpdateBook(details models.UpdateBook, id, actor string) (models.Book, error)
+	RevertBook(id string, version int, actor string) (models.Book, error)
+	DeleteBook(id, actor string) (int64, error)
+	GetSlugRedirect(slug string) (string, error)
 }
 
 type AuthorI interface {
-	GetAuthor(id string) (models.Author, error)
+	GetAuthor(id string, fields ...string) (models.Author, error)
+	GetAuthorAsOf(id string, asOf time.Time, fields ...string) (models.Author, error)
 	GetAllAuthors(queryParam models.ApplicationQueryParamModel) ([]models.Author, error)
-	CreateAuthor(details models.Author) (string, error)
-	UpdateAuthor(details models.UpdateAuthor, id string) (int64, error)
-	DeleteAuthor(id string) (int64, error)
+	GetAuthorsByIDs(ids []string, fields ...string) ([]models.Author, error)
+	CreateAuthor(details models.Author, actor string) (models.Author, error)
+	UpdateAuthor(details models.UpdateAuthor, id, actor string) (models.Author, error)
+	DeleteAuthor(id, actor string) (int64, error)
+	GetAuthorNames() ([]models.Author, error)
+	GetAuthorRedirect(id string) (string, error)
+	GetSlugRedirect(slug string) (string, error)
+	MergeAuthors(targetID string, sourceIDs []string, actor string) (models.AuthorMerge, error)
+}
+
+type SeriesI interface {
+	GetSeries(id string) (models.Series, error)
+	GetAllSeries(queryParam models.ApplicationQueryParamModel) ([]models.Series, error)
+	CreateSeries(details models.Series) (string, error)
+	UpdateSeries(details models.UpdateSeries, id string) (int64, error)
+	DeleteSeries(id, actor string) (int64, error)
+	GetSeriesBooks(id string) ([]models.Book, error)
+	AddBookToSeries(seriesID, bookID string, position float64, actor string) (int64, error)
+	RemoveBookFromSeries(seriesID, bookID, actor string) (int64, error)
+}
+
+type CopyI interface {
+	GetCopy(bookID, id string) (models.Copy, error)
+	GetCopyByBarcode(barcode string) (models.Copy, error)
+	GetAllCopies(bookID string, queryParam models.ApplicationQueryParamModel) ([]models.Copy, error)
+	CreateCopy(details models.Copy, holdPickupPeriod time.Duration) (string, error)
+	UpdateCopy(details models.UpdateCopy, bookID, id string, holdPickupPeriod time.Duration) (int64, error)
+	DeleteCopy(bookID, id string) (int64, error)
+}
+
+type MemberI interface {
+	GetMember(id string) (models.Member, error)
+	GetAllMembers(queryParam models.ApplicationQueryParamModel) ([]models.Member, error)
+	CreateMember(details models.Member) (string, error)
+	UpdateMember(details models.UpdateMember, id string) (int64, error)
+	DeleteMember(id string) (int64, error)
+}
+
+type LoanI interface {
+	GetLoan(id string) (models.Loan, error)
+	GetAllLoans(queryParam models.LoanQueryParamModel) ([]models.Loan, error)
+	CreateLoan(details models.Loan, maxBalance int64) (string, error)
+	ReturnLoan(id string, returnedAt time.Time, fine int64, holdPickupPeriod time.Duration) (models.Loan, error)
+}
+
+type HoldI interface {
+	GetHold(id string) (models.Hold, error)
+	GetBookHolds(bookID string) ([]models.Hold, error)
+	GetMemberHolds(memberID string) ([]models.Hold, error)
+	CreateHold(details models.Hold) (string, error)
+	CancelHold(id string, pickupPeriod time.Duration) (int64, error)
+	ExpireHolds(pickupPeriod time.Duration) (int64, error)
+}
+
+type FineI interface {
+	GetMemberFines(memberID string, queryParam models.ApplicationQueryParamModel) (models.FineAccount, error)
+	CreateFine(details models.Fine) (string, error)
+}
+
+type ReviewI interface {
+	GetReview(bookID, id string) (models.Review, error)
+	GetAllReviews(bookID string, queryParam models.ApplicationQueryParamModel) ([]models.Review, error)
+	CreateReview(details models.Review, actor string) (string, error)
+	UpdateReview(details models.UpdateReview, bookID, id, actor string) (int64, error)
+	DeleteReview(bookID, id, actor string) (int64, error)
+}
+
+type CoverI interface {
+	GetCover(bookID string) (models.Cover, error)
+	UpsertCover(details models.Cover) error
+}
+
+// WebhookI manages webhook subscriptions and the queue of their deliveries, which the
+// dispatcher drains.
+type WebhookI interface {
+	CreateWebhook(details models.Webhook) (models.Webhook, error)
+	GetWebhook(id string) (models.Webhook, error)
+	GetAllWebhooks(queryParam models.ApplicationQueryParamModel) ([]models.Webhook, error)
+	UpdateWebhook(details models.UpdateWebhook, id string) (models.Webhook, error)
+	DeleteWebhook(id string) (int64, error)
+	GetDeliveries(webhookID string, queryParam models.WebhookDeliveryQueryParamModel) ([]models.WebhookDelivery, error)
+	GetDelivery(webhookID, id string) (models.WebhookDelivery, error)
+	RetryDelivery(webhookID, id string) (int64, error)
+	FanOutEvents(limit int) (int64, error)
+	ClaimDeliveries(limit int, lease time.Duration) ([]models.DueDelivery, error)
+	RecordAttempt(deliveryID string, attempt models.WebhookAttempt, delivered bool, retryAt *time.Time) error
+}
+
+// EventI reads the catalog events in the order they were committed.
+type EventI interface {
+	GetEvents(afterID int64, entities []string, limit int) ([]models.StoredEvent, error)
+	LatestEventID() (int64, error)
+	GetChangedEntities(afterID int64, limit int) ([]models.EntityChange, error)
+}
+
+// AuditI reads the change history that the book, author and category repos record.
+// entity is the table name, e.g. "book".
+type AuditI interface {
+	GetHistory(entity, id string, queryParam models.ApplicationQueryParamModel) ([]models.AuditEntry, error)
 }
@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/saidakhmatov/catalog_of_books/config"
+	"github.com/saidakhmatov/catalog_of_books/models"
+	"github.com/saidakhmatov/catalog_of_books/storage"
+)
+
+// Headers sent with every delivery. Receivers verify a delivery by computing Sign with
+// their secret over the timestamp and the raw body and comparing it with the signature.
+const (
+	HeaderEvent     = "X-Catalog-Event"
+	HeaderDelivery  = "X-Catalog-Delivery"
+	HeaderTimestamp = "X-Catalog-Timestamp"
+	HeaderSignature = "X-Catalog-Signature"
+)
+
+// batchSize caps how many events are fanned out and deliveries sent per round.
+const batchSize = 100
+
+// Sign returns the signature of a delivery, "sha256=" followed by the hex encoded
+// HMAC-SHA256 of the Unix timestamp, a dot and the body, keyed with the webhook secret.
+// Signing the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret returns a random secret for a webhook created without one.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Dispatcher delivers the events queued in the outbox to the subscribed webhooks.
+type Dispatcher struct {
+	repo   storage.WebhookI
+	client *http.Client
+	cfg    config.Config
+}
+
+func NewDispatcher(repo storage.WebhookI, cfg config.Config) *Dispatcher {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !cfg.WebhookAllowPrivate {
+		dialer.Control = refusePrivate
+	}
+
+	// No proxy, so that the address checked is the one delivered to.
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &Dispatcher{
+		repo: repo,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(cfg.WebhookTimeoutSeconds) * time.Second,
+		},
+		cfg: cfg,
+	}
+}
+
+// refusePrivate refuses connections to loopback, link-local, private and unspecified
+// addresses, so that a webhook cannot make the dispatcher post catalog data to the
+// services around it. It runs on the resolved address of every connection, redirects
+// included, so a host name that resolves to such an address is refused as well.
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("webhook address %s is not an IP address", host)
+	}
+
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("webhook address %s is not public", ip)
+	}
+
+	return nil
+}
+
+// Run dispatches on every notification of a catalog change and on every poll interval,
+// which picks up retries that have come due, until the process exits.
+func (d *Dispatcher) Run(notifications <-chan models.Notification) {
+	poll := time.NewTicker(time.Duration(d.cfg.WebhookPollSeconds) * time.Second)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-notifications:
+		case <-poll.C:
+		}
+
+		if err := d.Dispatch(); err != nil {
+			log.Println("Could not dispatch webhooks:", err)
+		}
+	}
+}
+
+// Dispatch fans new outbox events out to the webhooks and sends the deliveries that are
+// due, draining both queues a batch at a time.
+func (d *Dispatcher) Dispatch() error {
+	for {
+		n, err := d.repo.FanOutEvents(batchSize)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	// A claimed delivery is held back from other dispatchers for as long as sending it
+	// may take, so a dispatcher that dies mid-batch only delays it.
+	lease := d.client.Timeout*batchSize + time.Minute
+
+	for {
+		deliveries, err := d.repo.ClaimDeliveries(batchSize, lease)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		for _, delivery := range deliveries {
+			if err := d.deliver(delivery); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// deliver sends one delivery and records the attempt. Any 2xx answer counts as delivered.
+func (d *Dispatcher) deliver(delivery models.DueDelivery) error {
+	attempt := models.WebhookAttempt{Attempt: delivery.Attempts + 1}
+
+	start := time.Now()
+	statusCode, err := d.send(delivery)
+	attempt.DurationMS = int(time.Since(start).Milliseconds())
+
+	if statusCode > 0 {
+		attempt.StatusCode = &statusCode
+	}
+
+	delivered := err == nil
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	var retryAt *time.Time
+	if !delivered && attempt.Attempt < d.cfg.WebhookMaxAttempts {
+		at := time.Now().Add(d.cfg.WebhookRetryDelay(attempt.Attempt))
+		retryAt = &at
+	}
+
+	return d.repo.RecordAttempt(delivery.ID, attempt, delivered, retryAt)
+}
+
+func (d *Dispatcher) send(delivery models.DueDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", d.cfg.ProjectName+"-webhooks/"+d.cfg.Version)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}